@@ -0,0 +1,88 @@
+package shellquote
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// ansiEscapes maps the single-character escapes of $'...' strings to the
+// characters they stand for.
+var ansiEscapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'e': 0x1b, 'E': 0x1b, 'f': '\f', 'n': '\n',
+	'r': '\r', 't': '\t', 'v': '\v', '\\': '\\', '\'': '\'', '"': '"',
+	'?': '?',
+}
+
+// ansiEscape writes the character represented by the escape sequence at the
+// start of input, which comes just after a backslash inside a $'...' string,
+// and returns the length of the sequence. Like bash, it leaves unknown
+// escapes alone, keeping the backslash. This includes a backslash followed
+// by a newline, which isn't a line continuation inside $'...'.
+func ansiEscape(input string, buf *bytes.Buffer) int {
+	if len(input) == 0 {
+		return 0
+	}
+	c := input[0]
+	if r, ok := ansiEscapes[c]; ok {
+		buf.WriteByte(r)
+		return 1
+	}
+	switch c {
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		v, n := parseDigits(input, 8, 3)
+		buf.WriteByte(byte(v))
+		return n
+	case 'x':
+		if v, n := parseDigits(input[1:], 16, 2); n > 0 {
+			buf.WriteByte(byte(v))
+			return 1 + n
+		}
+	case 'u':
+		if v, n := parseDigits(input[1:], 16, 4); n > 0 {
+			buf.WriteRune(rune(v))
+			return 1 + n
+		}
+	case 'U':
+		if v, n := parseDigits(input[1:], 16, 8); n > 0 {
+			buf.WriteRune(rune(v))
+			return 1 + n
+		}
+	case 'c':
+		// \cx is the control character corresponding to x, but the quote
+		// closing the string can't be x
+		if len(input) > 1 && input[1] < utf8.RuneSelf && input[1] != '\'' {
+			buf.WriteByte(input[1] & 0x1f)
+			return 2
+		}
+	}
+	_, l := utf8.DecodeRuneInString(input)
+	buf.WriteRune(escapeChar)
+	buf.WriteString(input[:l])
+	return l
+}
+
+// parseDigits parses up to max digits in the given base from the start of
+// input, returning the value and the number of digits used.
+func parseDigits(input string, base, max int) (v, n int) {
+	for n < max && n < len(input) {
+		d := digitVal(input[n])
+		if d >= base {
+			break
+		}
+		v = v*base + d
+		n++
+	}
+	return v, n
+}
+
+func digitVal(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c - 'a' + 10)
+	case 'A' <= c && c <= 'F':
+		return int(c - 'A' + 10)
+	}
+	return 16
+}
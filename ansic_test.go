@@ -0,0 +1,51 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestANSIC(t *testing.T) {
+	opts := Options{ANSIC: true}
+	for _, elem := range ansiCTest {
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+
+	for _, input := range []string{`$'abc`, `$'abc\'`, `$'abc\`} {
+		if _, err := opts.Split(input); err != UnterminatedSingleQuoteError {
+			t.Errorf("Input %q, got error %#v, expected %#v", input, err, UnterminatedSingleQuoteError)
+		}
+	}
+
+	// without the option, $'...' is just a $ followed by a single-quoted
+	// string
+	var plain Options
+	output, err := plain.Split(`$'a\nb'`)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if expected := []string{`$a\nb`}; !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+}
+
+var ansiCTest = []struct {
+	input  string
+	output []string
+}{
+	{`$'a\nb'`, []string{"a\nb"}},
+	{"$'a\\\nb'", []string{"a\\\nb"}},
+	{`$'tab\there' $'\a\b\e\E\f\r\v'`, []string{"tab\there", "\a\b\x1b\x1b\f\r\v"}},
+	{`$'\x41\x4g\101\0é\U0001F600'`, []string{"A\x04gA\x00é😀"}},
+	{`$'\x' $'\u' $'\q'`, []string{`\x`, `\u`, `\q`}},
+	{`$'it\'s' $'\\' $'\"\?'`, []string{"it's", `\`, `"?`}},
+	{`$'\cA\c['`, []string{"\x01\x1b"}},
+	{`$'\c' $'a\c'b`, []string{`\c`, `a\cb`}},
+	{`pre$'\n'post "$'x'"`, []string{"pre\npost", "$'x'"}},
+	{`$'a b' c`, []string{"a b", "c"}},
+}
@@ -27,3 +27,28 @@ func TestJoinSplit(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestJoinSplitEmpty(t *testing.T) {
+	for _, strs := range [][]string{{""}, {"a", "", "b"}, {"", ""}, {"", "x", ""}} {
+		combined := Join(strs...)
+		split, err := Split(combined)
+		if err != nil {
+			t.Errorf("Error splitting %q: %v", combined, err)
+		} else if !reflect.DeepEqual(strs, split) {
+			t.Errorf("Input %q did not match output %q", strs, split)
+		}
+	}
+}
+
+func TestJoinSplitSpecialChars(t *testing.T) {
+	for _, c := range specialChars + extraSpecialChars + prefixChars + "#=%^,:" {
+		strs := []string{string(c), "a" + string(c) + "b", string(c) + string(c)}
+		combined := Join(strs...)
+		split, err := Split(combined)
+		if err != nil {
+			t.Errorf("Error splitting %q: %v", combined, err)
+		} else if !reflect.DeepEqual(strs, split) {
+			t.Errorf("Input %q did not match output %q", strs, split)
+		}
+	}
+}
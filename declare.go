@@ -0,0 +1,104 @@
+package shellquote
+
+import "strings"
+
+// ParseDeclare parses shell variable definitions, as printed by "declare -p",
+// "export -p" or "set", and returns the values of the variables defined.
+// Each line is either an assignment such as NAME='value', or a declare,
+// typeset, export or readonly command whose flags are skipped, as in
+// declare -x NAME="value". Values have their quotes and escapes processed,
+// including $'...' strings, and a quoted value may span several lines.
+//
+// A variable declared without a value, as in "declare -x NAME", is left out,
+// since it is unset. So are arrays, which are declared with the -a or -A
+// flag or assigned as NAME=(...), and the function definitions that "set"
+// prints after the variables. Any other word that isn't an assignment to a
+// valid variable name results in ErrInvalidName.
+func ParseDeclare(input string) (map[string]string, error) {
+	vars := make(map[string]string)
+	var words []Token
+	apply := func() error {
+		defer func() { words = words[:0] }()
+		if len(words) == 0 {
+			return nil
+		}
+		args := words
+		declared := false
+		switch args[0].Value {
+		case "declare", "typeset", "export", "readonly":
+			args = args[1:]
+			declared = true
+			for len(args) > 0 && strings.HasPrefix(args[0].Value, "-") {
+				if strings.ContainsAny(args[0].Value, "aA") {
+					// arrays can't be represented
+					return nil
+				}
+				args = args[1:]
+			}
+		}
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg.Value, "=")
+			if !isName(name) || (!ok && !declared) {
+				return ErrInvalidName
+			}
+			if strings.HasPrefix(arg.Raw, name+"=(") {
+				// neither can arrays assigned without declare
+				return nil
+			}
+			if ok {
+				vars[name] = value
+			}
+		}
+		return nil
+	}
+
+	opts := Options{PreserveWhitespace: true, ANSIC: true}
+	for len(input) > 0 {
+		next := ""
+		err := opts.scan(input, func(tok Token) error {
+			if tok.Kind == Word {
+				words = append(words, tok)
+				return nil
+			}
+			if strings.Count(tok.Raw, "\n") == strings.Count(tok.Raw, "\\\n") {
+				return nil
+			}
+			// an unescaped newline ends the definition
+			if isFunctionHeader(words) {
+				words = words[:0]
+				next = skipFunctionBody(input[tok.Offset:])
+				return errStopScan
+			}
+			return apply()
+		})
+		if err != nil {
+			return nil, err
+		}
+		input = next
+	}
+	if err := apply(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// isFunctionHeader reports whether words are the first line of a function
+// definition as printed by "set", such as "name () ".
+func isFunctionHeader(words []Token) bool {
+	return len(words) == 2 && words[1].Raw == "()"
+}
+
+// skipFunctionBody returns what follows the function body at the start of
+// input, which ends at the first line consisting of only a "}".
+func skipFunctionBody(input string) string {
+	for i := 0; ; {
+		j := strings.Index(input[i:], "\n}")
+		if j == -1 {
+			return ""
+		}
+		i += j + 2
+		if i == len(input) || input[i] == '\n' {
+			return input[i:]
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDeclare(t *testing.T) {
+	input := `declare -x HOME="/home/user"
+declare -x LESS="-R -F"
+declare -rx MSG="say \"hi\" for \$5
+and a second line"
+declare -- PS1='\u@\h:\w\$ '
+declare -x UNSET_EXPORTED
+declare -a ARR=([0]="a" [1]="b")
+declare -A MAP=([k]="v" )
+export EDITOR='vim' PAGER=less
+IT='it'\''s'
+EMPTY=
+declare -- TAB=$'a\tb'
+BASH_VERSINFO=([0]="5" [1]="2" [2]="21" [3]="1" [4]="release" [5]="x86_64-pc-linux-gnu")
+BASH_ARGV=()
+IFS=$' \t\n'
+PS4='+ '
+greet () 
+{ 
+    local msg="hi $1";
+    echo "$(printf '%s' "$msg")"
+}
+AFTER=1
+quote () 
+{ 
+    local quoted=${1//\'/\'\\\'\'};
+    printf "'%s'" "$quoted"
+}
+`
+	output, err := ParseDeclare(input)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := map[string]string{
+		"HOME":   "/home/user",
+		"LESS":   "-R -F",
+		"MSG":    "say \"hi\" for $5\nand a second line",
+		"PS1":    `\u@\h:\w\$ `,
+		"EDITOR": "vim",
+		"PAGER":  "less",
+		"IT":     "it's",
+		"EMPTY":  "",
+		"TAB":    "a\tb",
+		"IFS":    " \t\n",
+		"PS4":    "+ ",
+		"AFTER":  "1",
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	for _, input := range []string{"declare -x 1BAD=x", "echo hi", "A=1\n=2"} {
+		if _, err := ParseDeclare(input); err != ErrInvalidName {
+			t.Errorf("Input %q, got error %#v, expected %#v", input, err, ErrInvalidName)
+		}
+	}
+	if _, err := ParseDeclare(`A="unterminated`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+}
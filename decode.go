@@ -0,0 +1,36 @@
+package shellquote
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitDecoder splits input like Split, after decoding it with dec. This
+// allows splitting text in encodings other than UTF-8: dec is called with the
+// remaining bytes and returns the next rune along with the number of bytes it
+// occupies. If dec is nil, input is decoded as UTF-8.
+//
+// Decoding happens before splitting, so bytes that are only part of a
+// multibyte character (such as a Shift-JIS trail byte that happens to be a
+// backslash) are never mistaken for quotes or escapes.
+func SplitDecoder(input []byte, dec func([]byte) (rune, int)) ([]string, error) {
+	if dec == nil {
+		dec = utf8.DecodeRune
+	}
+	var b strings.Builder
+	b.Grow(len(input))
+	for len(input) > 0 {
+		r, n := dec(input)
+		if n < 1 {
+			// don't loop forever on a decoder that makes no progress
+			r, n = utf8.RuneError, 1
+		}
+		if n > len(input) {
+			// nor crash on one that claims more bytes than are left
+			n = len(input)
+		}
+		b.WriteRune(r)
+		input = input[n:]
+	}
+	return Split(b.String())
+}
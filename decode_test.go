@@ -0,0 +1,44 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func decodeLatin1(b []byte) (rune, int) {
+	return rune(b[0]), 1
+}
+
+func TestSplitDecoder(t *testing.T) {
+	output, err := SplitDecoder([]byte("caf\xe9 'na\xefve \xbf?'"), decodeLatin1)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []string{"café", "naïve ¿?"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	output, err = SplitDecoder([]byte("café 'naïve'"), nil)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected = []string{"café", "naïve"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	// a decoder reporting more bytes than are left uses up the rest
+	output, err = SplitDecoder([]byte("ab"), func(b []byte) (rune, int) { return rune(b[0]), 5 })
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected = []string{"a"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	if _, err := SplitDecoder([]byte("'\xe9"), decodeLatin1); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
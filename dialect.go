@@ -0,0 +1,75 @@
+package shellquote
+
+import "strings"
+
+// DetectDialect guesses which shell input was written for. It is a
+// best-effort heuristic, meant for command strings of unknown origin.
+//
+// Each dialect other than Sh scores a point for every marker of it found in
+// input:
+//
+//   - Bash: $'...' strings, [[, process substitution with <( or >(, the &>
+//     redirection and brace expansion such as {a,b}.
+//   - PowerShell: a backtick escaping a quote, a dollar sign or a space, and
+//     $env: variables, which score two points each.
+//   - Cmd: a caret escaping one of &|<>^" and %VAR% expansions.
+//
+// The dialect with the highest score wins. Sh is returned if nothing scores,
+// or if the highest score is shared, since plain POSIX syntax is the most
+// common case.
+func DetectDialect(input string) Shell {
+	var scores [Cmd + 1]int
+	for i := 0; i < len(input); i++ {
+		rest := input[i:]
+		switch input[i] {
+		case '$':
+			if strings.HasPrefix(rest, "$'") {
+				scores[Bash]++
+			} else if strings.HasPrefix(strings.ToLower(rest), "$env:") {
+				scores[PowerShell] += 2
+			}
+		case '[':
+			if strings.HasPrefix(rest, "[[") {
+				scores[Bash]++
+				i++
+			}
+		case '<', '>':
+			if strings.HasPrefix(rest[1:], "(") {
+				scores[Bash]++
+			}
+		case '&':
+			if strings.HasPrefix(rest, "&>") {
+				scores[Bash]++
+			}
+		case '`':
+			if len(rest) > 1 && strings.IndexByte("\"'$ ", rest[1]) != -1 {
+				scores[PowerShell]++
+				i++
+			}
+		case '^':
+			if len(rest) > 1 && strings.IndexByte("&|<>^\"", rest[1]) != -1 {
+				scores[Cmd]++
+				i++
+			}
+		}
+	}
+	for _, word := range strings.Fields(input) {
+		if hasBraceExpansion(word) {
+			scores[Bash]++
+		}
+	}
+	scores[Cmd] += len(FindWinExpansions(input))
+
+	best, tied := Sh, false
+	for shell, score := range scores {
+		if score > scores[best] {
+			best, tied = Shell(shell), false
+		} else if score > 0 && score == scores[best] && Shell(shell) != best {
+			tied = true
+		}
+	}
+	if tied {
+		return Sh
+	}
+	return best
+}
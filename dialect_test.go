@@ -0,0 +1,31 @@
+package shellquote
+
+import "testing"
+
+func TestDetectDialect(t *testing.T) {
+	for _, elem := range detectDialectTest {
+		output := DetectDialect(elem.input)
+		if output != elem.output {
+			t.Errorf("Input %q, got %v, expected %v", elem.input, output, elem.output)
+		}
+	}
+}
+
+var detectDialectTest = []struct {
+	input  string
+	output Shell
+}{
+	{`ls -l "my dir"`, Sh},
+	{``, Sh},
+	{`echo $(date) | grep 'x' > out`, Sh},
+	{`echo $'a\tb'`, Bash},
+	{`if [[ -f x ]]; then cat x; fi`, Bash},
+	{`diff <(sort a) <(sort b) &> log`, Bash},
+	{`cp file.{txt,bak}`, Bash},
+	{"Write-Host `\"quoted`\" `$notvar", PowerShell},
+	{`Write-Output $env:PATH`, PowerShell},
+	{`echo a ^& b ^| c`, Cmd},
+	{`echo %USERPROFILE%\Desktop`, Cmd},
+	{`echo $'x' ^&`, Sh},
+	{"echo `date`", Sh},
+}
@@ -0,0 +1,54 @@
+package shellquote
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StripQuotes splits input like Split and joins the resulting words with
+// single spaces, producing a flattened form of the command without any
+// quoting. This is only meant for display: the result is lossy and will not
+// in general split back into the same words.
+func StripQuotes(input string) (string, error) {
+	words, err := Split(input)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(words, " "), nil
+}
+
+// SplitSquash splits input like Split, then collapses each run of whitespace
+// inside a word, such as in a quoted string, to a single space. Like
+// StripQuotes, this is only meant for display, such as in a compact summary
+// of a command: it is lossy, and the words can't be used to run the command.
+func SplitSquash(input string) ([]string, error) {
+	words, err := Split(input)
+	if err != nil {
+		return nil, err
+	}
+	for i, word := range words {
+		words[i] = squashSpace(word)
+	}
+	return words, nil
+}
+
+// squashSpace replaces each run of whitespace in s with a single space.
+func squashSpace(s string) string {
+	if strings.IndexFunc(s, unicode.IsSpace) == -1 {
+		return s
+	}
+	var b strings.Builder
+	inSpace := false
+	for _, c := range s {
+		if unicode.IsSpace(c) {
+			if !inSpace {
+				b.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		b.WriteRune(c)
+		inSpace = false
+	}
+	return b.String()
+}
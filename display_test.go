@@ -0,0 +1,55 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripQuotes(t *testing.T) {
+	for _, elem := range stripQuotesTest {
+		output, err := StripQuotes(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if output != elem.output {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+	if _, err := StripQuotes(`echo "unterminated`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedDoubleQuoteError)
+	}
+}
+
+func TestSplitSquash(t *testing.T) {
+	for _, elem := range splitSquashTest {
+		output, err := SplitSquash(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+	if _, err := SplitSquash(`echo 'unterminated`); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
+
+var stripQuotesTest = []struct {
+	input  string
+	output string
+}{
+	{`echo "hello world" 'a b'`, "echo hello world a b"},
+	{"  spaced   out\t", "spaced out"},
+	{`say "\"hi\"" '' there`, `say "hi"  there`},
+	{"", ""},
+}
+
+var splitSquashTest = []struct {
+	input  string
+	output []string
+}{
+	{`'a    b'`, []string{"a b"}},
+	{"echo  plain   words", []string{"echo", "plain", "words"}},
+	{"\"line one\n\n\tline two\" ' lead and trail  '", []string{"line one line two", " lead and trail "}},
+	{`a\ \ \ b`, []string{"a b"}},
+	{`"" x`, []string{"", "x"}},
+}
@@ -0,0 +1,41 @@
+//go:build go1.23
+
+package shellquote
+
+import "iter"
+
+// EventKind identifies the kind of an event reported by Events.
+type EventKind int
+
+const (
+	// WordEvent is a word, with its quotes and escapes processed.
+	WordEvent EventKind = iota
+	// SeparatorEvent is a run of separators between words, copied verbatim.
+	SeparatorEvent
+	// ErrorEvent reports a syntax error, whose message is the event's
+	// value. It is always the last event.
+	ErrorEvent
+)
+
+// Events returns an iterator over the words of input and the separators
+// around them, in the order they appear. Words are split as by Split. The
+// separators include any leading or trailing run, so concatenating the
+// values of an input without quotes or escapes reproduces it exactly.
+func Events(input string) iter.Seq2[EventKind, string] {
+	return func(yield func(EventKind, string) bool) {
+		opts := Options{PreserveWhitespace: true}
+		err := opts.scan(input, func(tok Token) error {
+			kind := WordEvent
+			if tok.Kind == Whitespace {
+				kind = SeparatorEvent
+			}
+			if !yield(kind, tok.Value) {
+				return errStopScan
+			}
+			return nil
+		})
+		if err != nil {
+			yield(ErrorEvent, err.Error())
+		}
+	}
+}
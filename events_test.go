@@ -0,0 +1,70 @@
+//go:build go1.23
+
+package shellquote
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	for _, input := range []string{"", "a", "  ls -l\t/tmp  ", "a \\\n b\n"} {
+		var b strings.Builder
+		for _, value := range Events(input) {
+			b.WriteString(value)
+		}
+		if b.String() != input {
+			t.Errorf("Input %q, reconstructed %q", input, b.String())
+		}
+	}
+
+	type event struct {
+		kind  EventKind
+		value string
+	}
+	var events []event
+	for kind, value := range Events(` echo 'a b'  "c"\d`) {
+		events = append(events, event{kind, value})
+	}
+	expected := []event{
+		{SeparatorEvent, " "},
+		{WordEvent, "echo"},
+		{SeparatorEvent, " "},
+		{WordEvent, "a b"},
+		{SeparatorEvent, "  "},
+		{WordEvent, "cd"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("got %v, expected %v", events, expected)
+	}
+}
+
+func TestEventsError(t *testing.T) {
+	var kinds []EventKind
+	var last string
+	for kind, value := range Events(`a 'b`) {
+		kinds = append(kinds, kind)
+		last = value
+	}
+	expected := []EventKind{WordEvent, SeparatorEvent, ErrorEvent}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("got %v, expected %v", kinds, expected)
+	}
+	if last != UnterminatedSingleQuoteError.Error() {
+		t.Errorf("got error message %q", last)
+	}
+}
+
+func TestEventsBreak(t *testing.T) {
+	n := 0
+	for range Events("a b c d 'unterminated") {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("got %d events, expected 3", n)
+	}
+}
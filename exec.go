@@ -0,0 +1,13 @@
+package shellquote
+
+import "runtime"
+
+// ExecArgs splits input into the arguments to pass to exec.Command, using the
+// rules of the platform the program is running on. On Windows, input is
+// split by SplitWindows, and elsewhere it is split by Split.
+func ExecArgs(input string) ([]string, error) {
+	if runtime.GOOS == "windows" {
+		return SplitWindows(input), nil
+	}
+	return Split(input)
+}
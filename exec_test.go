@@ -0,0 +1,22 @@
+package shellquote
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestExecArgs(t *testing.T) {
+	input := `cmd "a b" 'c d' e\"f`
+	expected := []string{"cmd", "a b", "c d", `e"f`}
+	if runtime.GOOS == "windows" {
+		expected = []string{"cmd", "a b", "'c", "d'", `e"f`}
+	}
+	output, err := ExecArgs(input)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+}
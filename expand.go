@@ -0,0 +1,270 @@
+package shellquote
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrBadSubstitution = errors.New("Bad substitution")
+
+// ParameterError is returned for a ${name:?message} expansion of a parameter
+// that is unset or empty.
+type ParameterError struct {
+	Name    string
+	Message string // the expanded message, or a default one if it was empty
+}
+
+func (e *ParameterError) Error() string {
+	return e.Name + ": " + e.Message
+}
+
+// specialParams are the single-character special parameters.
+const specialParams = "@*#?-$!"
+
+// expand parses the parameter expansion at the start of input, which comes
+// just after a "$", and looks it up with o.Lookup. It returns the number of
+// bytes that make up the parameter, or 0 if input doesn't start with one, in
+// which case the "$" is literal.
+//
+// Only $@ can result in more than one value; every other parameter results
+// in exactly one, which is empty if the parameter is unset. quoted reports
+// whether the expansion is inside double quotes.
+func (o *Options) expand(input string, quoted bool) (values []string, n int, err error) {
+	var name, op, word string
+	if strings.HasPrefix(input, "{") {
+		end := strings.IndexAny(input, ":}")
+		if end == -1 {
+			return nil, 0, ErrBadSubstitution
+		}
+		name = input[1:end]
+		if !isParamName(name) {
+			return nil, 0, ErrBadSubstitution
+		}
+		n = end + 1
+		if input[end] == ':' {
+			if len(input) < end+2 || strings.IndexByte("-=+?", input[end+1]) == -1 {
+				return nil, 0, ErrBadSubstitution
+			}
+			op = input[end : end+2]
+			wordLen := braceWordLen(input[end+2:])
+			if wordLen == -1 {
+				return nil, 0, ErrBadSubstitution
+			}
+			word = input[end+2 : end+2+wordLen]
+			n = end + 2 + wordLen + 1
+		}
+	} else {
+		n = paramNameLen(input)
+		if n == 0 {
+			return nil, 0, nil
+		}
+		name = input[:n]
+	}
+
+	values, ok := o.Lookup(name)
+	if op != "" {
+		set := ok && strings.Join(values, "") != ""
+		useWord := !set
+		if op == ":+" {
+			useWord = set
+			values = nil
+		}
+		if useWord {
+			// there is nothing to assign to for ":=", so it acts like ":-"
+			w, err := o.expandWord(word, quoted)
+			if err != nil {
+				return nil, 0, err
+			}
+			if op == ":?" {
+				if w == "" {
+					w = "parameter null or not set"
+				}
+				return nil, 0, &ParameterError{name, w}
+			}
+			values = []string{w}
+		}
+	}
+	if name != "@" {
+		values = []string{strings.Join(values, o.ifsSeparator())}
+	}
+	return values, n, nil
+}
+
+// SplitExpandPositional splits input like Split, expanding references to
+// the positional parameters in args, as for a script run with those
+// arguments. $0 is args[0], normally the program name, and $1 through $9,
+// ${10} and so on are the arguments after it. A parameter past the end of
+// args is unset, and so expands to nothing. $@, $* and $# expand to the
+// arguments after args[0] and their count. All other parameters are unset.
+func SplitExpandPositional(input string, args []string) ([]string, error) {
+	opts := Options{Lookup: func(name string) ([]string, bool) {
+		switch name {
+		case "@", "*":
+			if len(args) == 0 {
+				return nil, true
+			}
+			return args[1:], true
+		case "#":
+			if len(args) == 0 {
+				return []string{"0"}, true
+			}
+			return []string{strconv.Itoa(len(args) - 1)}, true
+		}
+		i, err := strconv.Atoi(name)
+		if err != nil || i >= len(args) {
+			return nil, false
+		}
+		return []string{args[i]}, true
+	}}
+	return opts.Split(input)
+}
+
+// braceWordLen returns the length of the word in a ${name:-word} style
+// expansion, up to but not including the closing brace, or -1 if there is no
+// closing brace. Quoted and escaped braces are skipped, and so are any
+// nested ${...} expansions.
+func braceWordLen(input string) int {
+	depth := 0
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '\'':
+			end := strings.IndexByte(input[i+1:], '\'')
+			if end == -1 {
+				return -1
+			}
+			i += 1 + end
+		case '"':
+			for i++; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(input) {
+				return -1
+			}
+		case '$':
+			if strings.HasPrefix(input[i+1:], "{") {
+				depth++
+				i++
+			}
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// expandWord processes the word of a ${name:-word} style expansion as a
+// single word, removing its quotes and expanding any parameters in it,
+// without splitting it at whitespace. If quoted is set, the expansion is
+// inside double quotes and so is the word, leaving single quotes in it
+// literal.
+func (o *Options) expandWord(word string, quoted bool) (string, error) {
+	w := *o
+	w.IsDelim = func(rune, bool) bool { return false }
+	w.Operators = false
+	if quoted {
+		w.KeepDoubleQuotes = false
+		word = `"` + word + `"`
+	}
+	// the output as a whole is limited, so the word can't be any longer
+	limit := -1
+	if w.MaxOutputLen > 0 {
+		limit = w.MaxOutputLen
+	}
+	var buf bytes.Buffer
+	fields, _, _, err := w.splitWord(word, &buf, limit)
+	if serr, ok := err.(*SyntaxError); ok {
+		err = serr.Err
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// ifsSeparator returns the string used to join the values of $*, which is
+// the first character of IFS, or a space if IFS is unset.
+func (o *Options) ifsSeparator() string {
+	values, ok := o.Lookup("IFS")
+	if !ok {
+		return " "
+	}
+	for _, c := range strings.Join(values, "") {
+		return string(c)
+	}
+	return ""
+}
+
+// paramNameLen returns the length of the parameter name at the start of an
+// unbraced expansion. Positional parameters are a single digit.
+func paramNameLen(input string) int {
+	if input == "" {
+		return 0
+	}
+	c := input[0]
+	if strings.IndexByte(specialParams, c) != -1 || isDigit(c) {
+		return 1
+	}
+	if !isNameStart(c) {
+		return 0
+	}
+	i := 1
+	for i < len(input) && isNameChar(input[i]) {
+		i++
+	}
+	return i
+}
+
+// isParamName reports whether name is a valid parameter name inside braces:
+// an identifier, a positional parameter of any number of digits, or a
+// special parameter.
+func isParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	if len(name) == 1 && strings.IndexByte(specialParams, name[0]) != -1 {
+		return true
+	}
+	if isDigit(name[0]) {
+		for i := 1; i < len(name); i++ {
+			if !isDigit(name[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return isName(name)
+}
+
+// isName reports whether name is a valid shell variable name.
+func isName(name string) bool {
+	if name == "" || !isNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
@@ -0,0 +1,145 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func lookupFrom(vars map[string][]string) func(string) ([]string, bool) {
+	return func(name string) ([]string, bool) {
+		values, ok := vars[name]
+		return values, ok
+	}
+}
+
+func TestExpand(t *testing.T) {
+	for _, elem := range expandTest {
+		opts := Options{Lookup: lookupFrom(elem.vars)}
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestErrorExpand(t *testing.T) {
+	opts := Options{Lookup: lookupFrom(nil)}
+	for _, input := range []string{"${bad name}", "${unterminated", `"${}"`} {
+		if _, err := opts.Split(input); err != ErrBadSubstitution {
+			t.Errorf("Input %q, got error %#v, expected %#v", input, err, ErrBadSubstitution)
+		}
+	}
+}
+
+func TestExpandOperators(t *testing.T) {
+	opts := Options{Lookup: lookupFrom(operatorVars)}
+	for _, elem := range expandOperatorsTest {
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+
+	for _, elem := range []struct{ input, message string }{
+		{"${EMPTY:?is empty}", "EMPTY: is empty"},
+		{"${UNSET:?$SET not set}", "UNSET: value not set"},
+		{`"${UNSET:?}"`, "UNSET: parameter null or not set"},
+	} {
+		_, err := opts.Split(elem.input)
+		if perr, ok := err.(*ParameterError); !ok || perr.Error() != elem.message {
+			t.Errorf("Input %q, got error %#v, expected %q", elem.input, err, elem.message)
+		}
+	}
+	for _, input := range []string{"${SET:-x", "${SET:x}", "${SET:", `${SET:-"}"`, "${SET:-${UNSET}"} {
+		if _, err := opts.Split(input); err != ErrBadSubstitution {
+			t.Errorf("Input %q, got error %#v, expected %#v", input, err, ErrBadSubstitution)
+		}
+	}
+}
+
+func TestSplitExpandPositional(t *testing.T) {
+	args := []string{"prog", "one", "two words", "3", "4", "5", "6", "7", "8", "9", "ten", "eleven"}
+	for _, elem := range splitExpandPositionalTest {
+		output, err := SplitExpandPositional(elem.input, args)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+	output, err := SplitExpandPositional(`$0 "$1" "$@" $#`, nil)
+	if err != nil {
+		t.Errorf("got error %#v", err)
+	} else if expected := []string{"", "0"}; !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+}
+
+var splitExpandPositionalTest = []struct {
+	input  string
+	output []string
+}{
+	{"$0 $1 ${10}", []string{"prog", "one", "ten"}},
+	{`"$2" $10 ${11} $9`, []string{"two words", "one0", "eleven", "9"}},
+	{`x${12}y "${99}" $HOME`, []string{"xy", ""}},
+	{`$# "$@"`, []string{"11", "one", "two words", "3", "4", "5", "6", "7", "8", "9", "ten", "eleven"}},
+	{`'$1' \$1 ${1:-default} ${20:-default}`, []string{"$1", "$1", "one", "default"}},
+}
+
+var (
+	operatorVars = map[string][]string{"SET": {"value"}, "EMPTY": {""}}
+
+	positionalVars = map[string][]string{
+		"@":    {"a b", "c"},
+		"*":    {"a b", "c"},
+		"HOME": {"/home/user"},
+	}
+	noPositionalVars = map[string][]string{"@": {}, "*": {}}
+	colonIFSVars     = map[string][]string{"@": {"a b", "c"}, "*": {"a b", "c"}, "IFS": {":"}}
+	emptyArgVars     = map[string][]string{"@": {""}, "*": {""}}
+)
+
+var expandTest = []struct {
+	vars   map[string][]string
+	input  string
+	output []string
+}{
+	{positionalVars, `echo "$@"`, []string{"echo", "a b", "c"}},
+	{positionalVars, `echo "$*"`, []string{"echo", "a b c"}},
+	{positionalVars, `echo $@`, []string{"echo", "a b", "c"}},
+	{positionalVars, `echo $*`, []string{"echo", "a b c"}},
+	{positionalVars, `echo x"$@"y`, []string{"echo", "xa b", "cy"}},
+	{positionalVars, `echo "${@}" "${*}"`, []string{"echo", "a b", "c", "a b c"}},
+	{colonIFSVars, `echo "$*"`, []string{"echo", "a b:c"}},
+	{noPositionalVars, `echo "$@"`, []string{"echo"}},
+	{noPositionalVars, `echo "${@}" "x${@}"`, []string{"echo", "x"}},
+	{noPositionalVars, `echo $@`, []string{"echo"}},
+	{noPositionalVars, `echo "$*"`, []string{"echo", ""}},
+	{noPositionalVars, `echo ""$@`, []string{"echo", ""}},
+	{emptyArgVars, `echo "$@"`, []string{"echo", ""}},
+	{emptyArgVars, `echo $@`, []string{"echo"}},
+	{positionalVars, `cd $HOME/src`, []string{"cd", "/home/user/src"}},
+	{positionalVars, `cd ${HOME}src "$HOME"`, []string{"cd", "/home/usersrc", "/home/user"}},
+	{positionalVars, `echo $UNSET "$UNSET" x$UNSET`, []string{"echo", "", "x"}},
+	{positionalVars, `echo '$HOME' \$HOME`, []string{"echo", "$HOME", "$HOME"}},
+	{positionalVars, `echo $ "$" $. a$`, []string{"echo", "$", "$", "$.", "a$"}},
+}
+
+var expandOperatorsTest = []struct {
+	input  string
+	output []string
+}{
+	{"${SET:-d} ${EMPTY:-d} ${UNSET:-d}", []string{"value", "d", "d"}},
+	{"${SET:=d} ${EMPTY:=d} ${UNSET:=d}", []string{"value", "d", "d"}},
+	{"${SET:+alt} ${EMPTY:+alt}x ${UNSET:+alt}y", []string{"alt", "x", "y"}},
+	{"${SET:?msg} ${SET:?}", []string{"value", "value"}},
+	{`${UNSET:-a b} "${UNSET:-a  b}"`, []string{"a b", "a  b"}},
+	{`${UNSET:-"q}" 'x'\}} ${UNSET:-}`, []string{"q} x}"}},
+	{`"${UNSET:-$SET}" ${UNSET:-${EMPTY:-nested}}!`, []string{"value", "nested!"}},
+	{`${UNSET:-{a}} ${SET:+${SET}s}`, []string{"{a}", "values"}},
+	{`"${UNSET:-'x'}" ${UNSET:-'x'} "${UNSET:-\$ \a}"`, []string{"'x'", "x", `$ \a`}},
+}
@@ -0,0 +1,78 @@
+package shellquote
+
+import (
+	"errors"
+	"strings"
+)
+
+// FormatError returns a message describing err, which resulted from
+// splitting input with the default options, followed by the line of input
+// where the error occurred and a caret under the offending position:
+//
+//	Unterminated double-quoted string
+//	echo "hello
+//	     ^
+//
+// If err doesn't relate to a location in input, only the message is
+// returned.
+func FormatError(input string, err error) string {
+	var opts Options
+	return opts.FormatError(input, err)
+}
+
+// FormatError is like the package-level FormatError, for an error that
+// resulted from splitting input with the settings in o.
+func (o *Options) FormatError(input string, err error) string {
+	if err == nil {
+		return ""
+	}
+	offset, ok := o.errorOffset(input, err)
+	if !ok || offset < 0 || offset > len(input) {
+		return err.Error()
+	}
+	var serr *SyntaxError
+	if errors.As(err, &serr) {
+		err = serr.Err
+	}
+
+	start := strings.LastIndexByte(input[:offset], '\n') + 1
+	end := strings.IndexByte(input[offset:], '\n')
+	if end == -1 {
+		end = len(input)
+	} else {
+		end += offset
+	}
+	line := input[start:end]
+
+	// the caret is placed by column rather than by byte offset, and tabs
+	// are kept so it lines up however they are displayed
+	var b strings.Builder
+	b.WriteString(err.Error())
+	b.WriteByte('\n')
+	b.WriteString(line)
+	b.WriteByte('\n')
+	for _, c := range input[start:offset] {
+		if c == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteByte('^')
+	return b.String()
+}
+
+// errorOffset finds the location of err within input. A *SyntaxError
+// records it directly. Otherwise input is split again to see if it results
+// in the same error.
+func (o *Options) errorOffset(input string, err error) (int, bool) {
+	var serr *SyntaxError
+	if errors.As(err, &serr) {
+		return serr.Offset, true
+	}
+	rescan := o.scanTokens(input, func(Token) error { return nil })
+	if errors.As(rescan, &serr) && serr.Err == err {
+		return serr.Offset, true
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package shellquote
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatError(t *testing.T) {
+	for _, elem := range formatErrorTest {
+		_, err := elem.opts.Split(elem.input)
+		output := elem.opts.FormatError(elem.input, err)
+		if output != elem.output {
+			t.Errorf("Input %q, got\n%s\nexpected\n%s", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestFormatSyntaxError(t *testing.T) {
+	input := "grep ünïcode |"
+	_, err := ParsePipeline(input)
+	output := FormatError(input, err)
+	expected := "Missing command\ngrep ünïcode |\n              ^"
+	if output != expected {
+		t.Errorf("got\n%s\nexpected\n%s", output, expected)
+	}
+
+	other := errors.New("something else")
+	if output := FormatError("echo 'unterminated", other); output != "something else" {
+		t.Errorf("got %q, expected the plain message", output)
+	}
+	if output := FormatError("echo", nil); output != "" {
+		t.Errorf("got %q for a nil error", output)
+	}
+}
+
+var formatErrorTest = []struct {
+	opts   Options
+	input  string
+	output string
+}{
+	{Options{}, `echo "hello`, "Unterminated double-quoted string\necho \"hello\n     ^"},
+	{Options{}, `héllo wörld 'ünterminated`, "Unterminated single-quoted string\nhéllo wörld 'ünterminated\n            ^"},
+	{Options{}, "first line\n\tsecond 'line\nthird", "Unterminated single-quoted string\n\tsecond 'line\n\t       ^"},
+	{Options{}, `日本 語\`, "Unterminated backslash-escape\n日本 語\\\n    ^"},
+	{Options{}, `a "b\`, "Unterminated double-quoted string\na \"b\\\n  ^"},
+	{Options{OpaqueSubstitution: true}, `ok "$(echo`, "Unterminated command substitution\nok \"$(echo\n    ^"},
+	{Options{Lookup: lookupFrom(nil)}, `x ${bad name}`, "Bad substitution\nx ${bad name}\n  ^"},
+}
@@ -0,0 +1,28 @@
+package shellquote
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// WordsHash splits input like Split and returns a hash of the resulting
+// words, so that commands that only differ in their quoting, such as
+// `echo "a b"` and `echo a\ b`, hash the same.
+//
+// The hash is 64-bit FNV-1a over each word preceded by its length as a
+// uvarint, which keeps ["ab"] and ["a" "b"] apart. It is stable across
+// releases and platforms, but it isn't cryptographic, so it mustn't be
+// relied on to tell apart untrusted commands.
+func WordsHash(input string) (uint64, error) {
+	words, err := Split(input)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	var n [binary.MaxVarintLen64]byte
+	for _, word := range words {
+		h.Write(n[:binary.PutUvarint(n[:], uint64(len(word)))])
+		h.Write([]byte(word))
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,51 @@
+package shellquote
+
+import "testing"
+
+func TestWordsHash(t *testing.T) {
+	for _, group := range wordsHashEqualTest {
+		first, err := WordsHash(group[0])
+		if err != nil {
+			t.Fatalf("Input %q, got error %#v", group[0], err)
+		}
+		for _, input := range group[1:] {
+			if h, _ := WordsHash(input); h != first {
+				t.Errorf("Input %q hashes to %x, but %q hashes to %x", input, h, group[0], first)
+			}
+		}
+	}
+
+	seen := make(map[uint64]string)
+	for _, input := range wordsHashDistinctTest {
+		h, err := WordsHash(input)
+		if err != nil {
+			t.Fatalf("Input %q, got error %#v", input, err)
+		}
+		if other, ok := seen[h]; ok {
+			t.Errorf("Inputs %q and %q both hash to %x", input, other, h)
+		}
+		seen[h] = input
+	}
+
+	if _, err := WordsHash(`a "b`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+}
+
+var wordsHashEqualTest = [][]string{
+	{`echo "a b"`, `echo a\ b`, `  echo 'a b'  `, `"echo" 'a'" "b`},
+	{``, `   `},
+	{`x ''`, `x ""`},
+}
+
+var wordsHashDistinctTest = []string{
+	``,
+	`''`,
+	`'' ''`,
+	`ab`,
+	`a b`,
+	`"a b"`,
+	`echo ab c`,
+	`echo a bc`,
+	`echo abc`,
+}
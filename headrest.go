@@ -0,0 +1,42 @@
+package shellquote
+
+import (
+	"strings"
+)
+
+// SplitHeadRest splits the first word off of input, returning it with its
+// quotes and escapes processed as by Split. The remainder of input is
+// returned verbatim as rest, with its quoting left intact and without being
+// checked for errors; scanning stops right after the head. The separators
+// between head and rest are dropped, but any trailing whitespace at the end
+// of rest is kept.
+//
+// This is useful for commands like alias, where the first word is a name
+// and the rest is an opaque command body.
+func SplitHeadRest(input string) (head string, rest string, err error) {
+	var opts Options
+	err = opts.scan(input, func(tok Token) error {
+		head = tok.Value
+		rest = trimSeparators(input[tok.Offset+len(tok.Raw):])
+		return errStopScan
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return head, rest, nil
+}
+
+// trimSeparators removes the leading separators from s, including any
+// backslash-escaped newlines.
+func trimSeparators(s string) string {
+	for len(s) > 0 {
+		if strings.ContainsRune(splitChars, rune(s[0])) {
+			s = s[1:]
+		} else if strings.HasPrefix(s, "\\\n") {
+			s = s[2:]
+		} else {
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package shellquote
+
+import (
+	"testing"
+)
+
+func TestSplitHeadRest(t *testing.T) {
+	for _, elem := range splitHeadRestTest {
+		head, rest, err := SplitHeadRest(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if head != elem.head || rest != elem.rest {
+			t.Errorf("Input %q, got %q, %q, expected %q, %q", elem.input, head, rest, elem.head, elem.rest)
+		}
+	}
+	if _, _, err := SplitHeadRest("'run echo"); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
+
+var splitHeadRestTest = []struct {
+	input string
+	head  string
+	rest  string
+}{
+	{`run echo "a b" c`, "run", `echo "a b" c`},
+	{`  'my cmd'   rm -rf "$dir"  `, "my cmd", `rm -rf "$dir"  `},
+	{"x=1 \\\n  body", "x=1", "body"},
+	{"alone", "alone", ""},
+	{"alone  ", "alone", ""},
+	{"", "", ""},
+	{`name 'unterminated body`, "name", `'unterminated body`},
+	{"run \\", "run", "\\"},
+	{"run \\\n", "run", ""},
+}
@@ -0,0 +1,44 @@
+package shellquote
+
+import (
+	"strings"
+)
+
+// IsIncomplete reports whether input is an incomplete command, for which an
+// interactive shell would prompt for another line. That is the case if it
+// ends inside a quoted string, in a backslash-escape or just after an
+// unquoted operator that needs a command after it: "|", "|&", "&&" or "||".
+// A final backslash-escaped newline also continues the command on the next
+// line.
+func IsIncomplete(input string) bool {
+	if strings.HasSuffix(input, "\n") {
+		escapes := 0
+		for i := len(input) - 2; i >= 0 && input[i] == '\\'; i-- {
+			escapes++
+		}
+		if escapes%2 == 1 {
+			return true
+		}
+	}
+	opts := Options{Operators: true}
+	var last Token
+	err := opts.scan(input, func(tok Token) error {
+		last = tok
+		return nil
+	})
+	switch err {
+	case nil:
+	case UnterminatedSingleQuoteError, UnterminatedDoubleQuoteError, UnterminatedEscapeError:
+		return true
+	default:
+		return false
+	}
+	if last.Kind != Operator {
+		return false
+	}
+	switch last.Value {
+	case "|", "|&", "&&", "||":
+		return true
+	}
+	return false
+}
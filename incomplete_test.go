@@ -0,0 +1,35 @@
+package shellquote
+
+import "testing"
+
+func TestIsIncomplete(t *testing.T) {
+	for _, elem := range isIncompleteTest {
+		if output := IsIncomplete(elem.input); output != elem.output {
+			t.Errorf("Input %q, got %v, expected %v", elem.input, output, elem.output)
+		}
+	}
+}
+
+var isIncompleteTest = []struct {
+	input  string
+	output bool
+}{
+	{"foo |", true},
+	{"foo | bar", false},
+	{`foo "|"`, false},
+	{`foo \|`, false},
+	{"foo &&", true},
+	{"foo ||\n", true},
+	{"foo |&  ", true},
+	{"foo &", false},
+	{"foo;", false},
+	{"foo >", false},
+	{`echo "abc`, true},
+	{"echo 'abc", true},
+	{`echo abc\`, true},
+	{"foo \\\n", true},
+	{"foo \\\\\n", false},
+	{"foo | \\\n", true},
+	{"", false},
+	{"foo", false},
+}
@@ -0,0 +1,25 @@
+package shellquote
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JoinJSON returns args as a JSON array of strings, as used by the exec form
+// of a Dockerfile's CMD and ENTRYPOINT instructions, such as
+// ["cmd","arg with space"]. Each argument is escaped as a JSON string rather
+// than quoted for a shell. HTML characters such as "<" and "&" aren't
+// escaped, since the result isn't meant for a web page. JSON strings can
+// only hold valid UTF-8, so each invalid byte in an argument is replaced by
+// U+FFFD, the Unicode replacement character.
+func JoinJSON(args ...string) string {
+	if args == nil {
+		args = []string{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// a []string can always be encoded
+	enc.Encode(args)
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
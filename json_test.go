@@ -0,0 +1,36 @@
+package shellquote
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJoinJSON(t *testing.T) {
+	for _, elem := range joinJSONTest {
+		output := JoinJSON(elem.input...)
+		if output != elem.output {
+			t.Errorf("Input %q, got %s, expected %s", elem.input, output, elem.output)
+		}
+		var decoded []string
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Errorf("Input %q, got invalid JSON %s: %v", elem.input, output, err)
+		} else if len(elem.input) > 0 && !reflect.DeepEqual(decoded, elem.input) {
+			t.Errorf("Input %q, decoded %q", elem.input, decoded)
+		}
+	}
+	if output, expected := JoinJSON("a\xffb"), "[\"a\ufffdb\"]"; output != expected {
+		t.Errorf("got %s, expected %s", output, expected)
+	}
+}
+
+var joinJSONTest = []struct {
+	input  []string
+	output string
+}{
+	{[]string{"cmd", "arg with space"}, `["cmd","arg with space"]`},
+	{[]string{`say "hi"`, `C:\dir\`, "it's"}, `["say \"hi\"","C:\\dir\\","it's"]`},
+	{[]string{"a\tb\nc", "x && y > z", ""}, `["a\tb\nc","x && y > z",""]`},
+	{[]string{"\x01", "é"}, `["\u0001","é"]`},
+	{nil, `[]`},
+}
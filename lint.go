@@ -0,0 +1,178 @@
+package shellquote
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Warning describes a likely mistake found by Lint.
+type Warning struct {
+	Offset  int // byte offset of the problem within the input
+	Message string
+}
+
+// Lint splits input like Split and reports any of the problems enabled by
+// the Warn fields of o. A syntax error, such as an unterminated quote, is
+// returned as an error rather than as a warning.
+func (o *Options) Lint(input string) (warnings []Warning, err error) {
+	warnings = make([]Warning, 0)
+	err = o.scan(input, func(tok Token) error {
+		if tok.Kind != Word {
+			return nil
+		}
+		if o.WarnMixedQuoting && tok.Quote&SingleQuoted != 0 && tok.Quote&DoubleQuoted != 0 {
+			warnings = append(warnings, Warning{tok.Offset, "word mixes single and double quotes"})
+		}
+		if o.WarnEmptyQuoteRun {
+			if i := emptyQuoteRun(tok.Raw, o.ANSIC); i >= 0 {
+				warnings = append(warnings, Warning{tok.Offset + i, "word contains a run of empty quotes"})
+			}
+		}
+		if o.WarnGlob {
+			if i := unquotedGlob(tok.Raw, o.ANSIC); i >= 0 {
+				msg := fmt.Sprintf("unquoted %q may be expanded as a glob", tok.Raw[i])
+				warnings = append(warnings, Warning{tok.Offset + i, msg})
+			}
+		}
+		if o.WarnHistoryExpansion {
+			if i := historyExpansion(tok.Raw, o.ANSIC); i >= 0 {
+				warnings = append(warnings, Warning{tok.Offset + i, `"!" may trigger history expansion`})
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// closingQuote returns the offset of the quote that closes the quoted string
+// opened by raw[i], which must be a single or double quote, or len(raw) if
+// the string is unterminated. If ansi is set, raw[i] is the single quote of
+// a $'...' string, whose escapes are skipped as in splitWord.
+func closingQuote(raw string, i int, ansi bool) int {
+	switch {
+	case ansi:
+		var scratch bytes.Buffer
+		for i++; i < len(raw) && raw[i] != '\''; i++ {
+			if raw[i] == '\\' {
+				n := ansiEscape(raw[i+1:], &scratch)
+				if n == 0 {
+					return len(raw)
+				}
+				i += n
+			}
+		}
+	case raw[i] == '\'':
+		if end := strings.IndexByte(raw[i+1:], '\''); end != -1 {
+			return i + 1 + end
+		}
+		return len(raw)
+	default:
+		for i++; i < len(raw) && raw[i] != '"'; i++ {
+			if raw[i] == '\\' {
+				i++
+			}
+		}
+	}
+	if i > len(raw) {
+		return len(raw)
+	}
+	return i
+}
+
+// ansiQuote reports whether raw[i] starts a $'...' string, which is only
+// the case if ansic is set.
+func ansiQuote(raw string, i int, ansic bool) bool {
+	return ansic && raw[i] == '$' && i+1 < len(raw) && raw[i+1] == '\''
+}
+
+// emptyQuoteRun returns the offset of the first run of two or more empty
+// quoted strings in a row within raw, or -1 if there is none. $'...'
+// strings count as quoted if ansic is set. raw must be free of syntax
+// errors.
+func emptyQuoteRun(raw string, ansic bool) int {
+	run, count := 0, 0
+	for i := 0; i < len(raw); i++ {
+		open := i
+		if ansiQuote(raw, i, ansic) {
+			i++
+		}
+		switch raw[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			end := closingQuote(raw, i, open != i)
+			if end == i+1 {
+				if count == 0 {
+					run = open
+				}
+				count++
+				if count == 2 {
+					return run
+				}
+				i = end
+				continue
+			}
+			i = end
+		}
+		count = 0
+	}
+	return -1
+}
+
+// unquotedGlob returns the offset of the first unquoted glob metacharacter in
+// raw, or -1 if there is none. A "[" only counts if a "]" follows it, and
+// $'...' strings are quoted if ansic is set. raw must be free of syntax
+// errors.
+func unquotedGlob(raw string, ansic bool) int {
+	for i := 0; i < len(raw); i++ {
+		if ansiQuote(raw, i, ansic) {
+			i = closingQuote(raw, i+1, true)
+			continue
+		}
+		switch raw[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			i = closingQuote(raw, i, false)
+		case '*', '?':
+			return i
+		case '[':
+			if strings.IndexByte(raw[i+1:], ']') != -1 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// historyExpansion returns the offset of the first "!" in raw that
+// interactive bash would treat as the start of a history expansion, or -1 if
+// there is none. That is any "!" that isn't single-quoted or escaped, unless
+// it is followed by whitespace, "=" or "(", or ends the word or a double
+// quoted string. $'...' strings are like single-quoted ones if ansic is
+// set. raw must be free of syntax errors.
+func historyExpansion(raw string, ansic bool) int {
+	inDouble := false
+	for i := 0; i < len(raw); i++ {
+		if !inDouble && ansiQuote(raw, i, ansic) {
+			i = closingQuote(raw, i+1, true)
+			continue
+		}
+		switch raw[i] {
+		case '\\':
+			i++
+		case '\'':
+			if !inDouble {
+				i = closingQuote(raw, i, false)
+			}
+		case '"':
+			inDouble = !inDouble
+		case '!':
+			if i+1 < len(raw) && strings.IndexByte(" \t\n=(\"", raw[i+1]) == -1 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,68 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	for _, elem := range lintTest {
+		output, err := elem.opts.Lint(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %#v, expected %#v", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestErrorLint(t *testing.T) {
+	opts := Options{WarnMixedQuoting: true}
+	for _, elem := range errorSplitTest {
+		_, err := opts.Lint(elem.input)
+		if err != elem.error {
+			t.Errorf("Input %q, got error %#v, expected error %#v", elem.input, err, elem.error)
+		}
+	}
+}
+
+const (
+	mixedQuotingWarning     = "word mixes single and double quotes"
+	emptyQuoteRunWarning    = "word contains a run of empty quotes"
+	historyExpansionWarning = `"!" may trigger history expansion`
+)
+
+var lintTest = []struct {
+	opts   Options
+	input  string
+	output []Warning
+}{
+	{Options{WarnMixedQuoting: true}, `echo a'b'"c"`, []Warning{{5, mixedQuotingWarning}}},
+	{Options{WarnMixedQuoting: true}, `echo a"bc"`, []Warning{}},
+	{Options{WarnMixedQuoting: true}, `'it'\''s' "fine"`, []Warning{}},
+	{Options{WarnMixedQuoting: true}, `"a"'b' x "c"'d'`, []Warning{{0, mixedQuotingWarning}, {9, mixedQuotingWarning}}},
+	{Options{}, `echo a'b'"c"`, []Warning{}},
+	{Options{WarnEmptyQuoteRun: true}, `''""`, []Warning{{0, emptyQuoteRunWarning}}},
+	{Options{WarnEmptyQuoteRun: true}, `'' 'x'`, []Warning{}},
+	{Options{WarnEmptyQuoteRun: true}, `echo a""''""b`, []Warning{{6, emptyQuoteRunWarning}}},
+	{Options{WarnEmptyQuoteRun: true}, `x ''a"" "\"" '' ""`, []Warning{}},
+	{Options{WarnEmptyQuoteRun: true}, `x "\""'' '"'""''`, []Warning{{12, emptyQuoteRunWarning}}},
+	{Options{WarnEmptyQuoteRun: true}, `\''' x`, []Warning{}},
+	{Options{WarnEmptyQuoteRun: true, ANSIC: true}, `echo $'\'"'`, []Warning{}},
+	{Options{WarnEmptyQuoteRun: true, ANSIC: true}, `echo $'\c'$''"" $'\\'''`, []Warning{{10, emptyQuoteRunWarning}}},
+	{Options{}, `''""`, []Warning{}},
+	{Options{WarnGlob: true}, `rm *.bak`, []Warning{{3, `unquoted '*' may be expanded as a glob`}}},
+	{Options{WarnGlob: true}, `rm "*.bak" '*' \* a"?"`, []Warning{}},
+	{Options{WarnGlob: true}, `ls file? x[0-9] [`, []Warning{{7, `unquoted '?' may be expanded as a glob`}, {10, `unquoted '[' may be expanded as a glob`}}},
+	{Options{WarnGlob: true}, `"a*"b*`, []Warning{{5, `unquoted '*' may be expanded as a glob`}}},
+	{Options{WarnGlob: true, ANSIC: true}, `echo $'\'"' *`, []Warning{{12, `unquoted '*' may be expanded as a glob`}}},
+	{Options{WarnGlob: true, ANSIC: true}, `echo $'*\'?'"[x]"`, []Warning{}},
+	{Options{}, `rm *.bak`, []Warning{}},
+	{Options{WarnHistoryExpansion: true}, `echo "!!" "!foo"`, []Warning{{6, historyExpansionWarning}, {11, historyExpansionWarning}}},
+	{Options{WarnHistoryExpansion: true}, `echo '!!' \!foo '"!x"'`, []Warning{}},
+	{Options{WarnHistoryExpansion: true}, `echo hi! "wow!" "a! b" a!=b !(x)`, []Warning{}},
+	{Options{WarnHistoryExpansion: true}, `sudo !!`, []Warning{{5, historyExpansionWarning}}},
+	{Options{WarnHistoryExpansion: true}, `x"a\"!y"`, []Warning{{5, historyExpansionWarning}}},
+	{Options{WarnHistoryExpansion: true, ANSIC: true}, `echo $'\'!x' $'\'"'!y`, []Warning{{19, historyExpansionWarning}}},
+	{Options{}, `sudo !!`, []Warning{}},
+}
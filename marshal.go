@@ -0,0 +1,49 @@
+package shellquote
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrBadEncoding = errors.New("Malformed word encoding")
+
+// MarshalWords splits input like Split and encodes the resulting words for
+// passing to another process, where UnmarshalWords decodes them. Each word is
+// written as its length in bytes, as a big-endian uint32, followed by the
+// word itself, so words may contain any bytes, including NUL.
+func MarshalWords(input string) ([]byte, error) {
+	words, err := Split(input)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, word := range words {
+		n += 4 + len(word)
+	}
+	data := make([]byte, n)
+	i := 0
+	for _, word := range words {
+		binary.BigEndian.PutUint32(data[i:], uint32(len(word)))
+		i += 4 + copy(data[i+4:], word)
+	}
+	return data, nil
+}
+
+// UnmarshalWords decodes words encoded by MarshalWords. If data is truncated
+// or otherwise malformed, ErrBadEncoding is returned.
+func UnmarshalWords(data []byte) ([]string, error) {
+	words := make([]string, 0)
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, ErrBadEncoding
+		}
+		n := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(n) > uint64(len(data)) {
+			return nil, ErrBadEncoding
+		}
+		words = append(words, string(data[:n]))
+		data = data[n:]
+	}
+	return words, nil
+}
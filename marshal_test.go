@@ -0,0 +1,39 @@
+package shellquote
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalWords(t *testing.T) {
+	args := []string{"printf", "a b", `say "hi"`, "it's", "nul\x00byte", "", "é"}
+	data, err := MarshalWords(Join(args...))
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	output, err := UnmarshalWords(data)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if !reflect.DeepEqual(output, args) {
+		t.Errorf("got %q, expected %q", output, args)
+	}
+
+	data, _ = MarshalWords(`ab ""`)
+	if expected := []byte("\x00\x00\x00\x02ab\x00\x00\x00\x00"); !bytes.Equal(data, expected) {
+		t.Errorf("got %q, expected %q", data, expected)
+	}
+	if output, err := UnmarshalWords(nil); err != nil || len(output) != 0 {
+		t.Errorf("got %q, %#v for no data", output, err)
+	}
+
+	if _, err := MarshalWords(`a "b`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+	for _, data := range []string{"\x00\x00\x01", "\x00\x00\x00\x03ab", "\x00\x00\x00\x00\xff\xff\xff\xff"} {
+		if _, err := UnmarshalWords([]byte(data)); err != ErrBadEncoding {
+			t.Errorf("Input %q, got error %#v, expected ErrBadEncoding", data, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package shellquote
+
+import "strings"
+
+// operatorChars are the characters that can begin an operator.
+const operatorChars = "|&;<>()"
+
+// operators lists the recognized control and redirection operators. A
+// longer operator must come before any operator that is a prefix of it, so
+// that matchOperator finds the longest match. The case statement terminators
+// ";;", ";&" and ";;&" are all distinct operators, and so is the "()" of a
+// function definition.
+var operators = []string{
+	"&>>", "&>", "&&", "&",
+	"||", "|&", "|",
+	";;&", ";;", ";&", ";",
+	"<<-", "<<", "<&", "<>", "<",
+	">>", ">&", ">|", ">",
+	"()", "(", ")",
+}
+
+// matchOperator returns the longest operator at the start of input, or "" if
+// input doesn't start with an operator.
+func matchOperator(input string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(input, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// startsCommand reports whether the word after op is at the start of a
+// command, as it is after a control operator but not after a redirection.
+func startsCommand(op string) bool {
+	switch op {
+	case "&&", "&", "||", "|&", "|", ";;&", ";;", ";&", ";", "()", "(":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOperatorSplit(t *testing.T) {
+	opts := Options{Operators: true}
+	for _, elem := range operatorSplitTest {
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestOperatorTokens(t *testing.T) {
+	opts := Options{Operators: true}
+	tokens, err := opts.Tokens(`a "&" '&&' \& &`)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	kinds := []TokenKind{Word, Word, Word, Word, Operator}
+	if len(tokens) != len(kinds) {
+		t.Fatalf("got %d tokens, expected %d: %#v", len(tokens), len(kinds), tokens)
+	}
+	for i, tok := range tokens {
+		if tok.Kind != kinds[i] {
+			t.Errorf("token %d (%q) has kind %v, expected %v", i, tok.Raw, tok.Kind, kinds[i])
+		}
+	}
+}
+
+func TestCaseTerminatorTokens(t *testing.T) {
+	opts := Options{Operators: true}
+	for _, elem := range caseTerminatorTest {
+		tokens, err := opts.Tokens(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+			continue
+		}
+		var ops []string
+		for _, tok := range tokens {
+			if tok.Kind == Operator {
+				ops = append(ops, tok.Value)
+			}
+		}
+		if !reflect.DeepEqual(ops, elem.output) {
+			t.Errorf("Input %q, got operators %q, expected %q", elem.input, ops, elem.output)
+		}
+	}
+}
+
+func TestKeywordTokens(t *testing.T) {
+	opts := Options{Operators: true}
+	for _, elem := range keywordTokensTest {
+		tokens, err := opts.Tokens(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+			continue
+		}
+		var kinds []TokenKind
+		for _, tok := range tokens {
+			kinds = append(kinds, tok.Kind)
+		}
+		if !reflect.DeepEqual(kinds, elem.output) {
+			t.Errorf("Input %q, got kinds %v, expected %v", elem.input, kinds, elem.output)
+		}
+	}
+
+	tokens, _ := opts.Tokens("greet() { echo hi; }")
+	var values []string
+	for _, tok := range tokens {
+		values = append(values, tok.Value)
+	}
+	expected := []string{"greet", "()", "{", "echo", "hi", ";", "}"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("got %q, expected %q", values, expected)
+	}
+}
+
+var keywordTokensTest = []struct {
+	input  string
+	output []TokenKind
+}{
+	{"greet() { echo hi; }", []TokenKind{Word, Operator, Keyword, Word, Word, Operator, Keyword}},
+	{"greet () {\n\techo hi\n}", []TokenKind{Word, Operator, Keyword, Word, Word, Keyword}},
+	{"echo { }", []TokenKind{Word, Word, Word}},
+	{`"{" echo '}'`, []TokenKind{Word, Word, Word}},
+	{"{ a; } > f", []TokenKind{Keyword, Word, Operator, Keyword, Operator, Word}},
+	{"a && {b; } | c", []TokenKind{Word, Operator, Word, Operator, Keyword, Operator, Word}},
+	{"x > { ; y \\\n }", []TokenKind{Word, Operator, Word, Operator, Word, Word}},
+	{"(a) ( b )", []TokenKind{Operator, Word, Operator, Operator, Word, Operator}},
+}
+
+var caseTerminatorTest = []struct {
+	input  string
+	output []string
+}{
+	{"a) foo;;", []string{")", ";;"}},
+	{"a) foo;&", []string{")", ";&"}},
+	{"a) foo;;&", []string{")", ";;&"}},
+	{"a) foo ;; b) bar ;& c) baz ;;& esac", []string{")", ";;", ")", ";&", ")", ";;&"}},
+	{"a;;;&", []string{";;", ";&"}},
+	{"a;&&b", []string{";&", "&"}},
+	{`a ';;&' \;\;`, nil},
+}
+
+var operatorSplitTest = []struct {
+	input  string
+	output []string
+}{
+	{"a & b", []string{"a", "&", "b"}},
+	{"a && b", []string{"a", "&&", "b"}},
+	{"a &> f", []string{"a", "&>", "f"}},
+	{"a &>> f", []string{"a", "&>>", "f"}},
+	{"a&b", []string{"a", "&", "b"}},
+	{"a&&b", []string{"a", "&&", "b"}},
+	{"a&>f", []string{"a", "&>", "f"}},
+	{"a&>>f", []string{"a", "&>>", "f"}},
+	{"a & & b", []string{"a", "&", "&", "b"}},
+	{"a &&& b", []string{"a", "&&", "&", "b"}},
+	{"a \"&\" b", []string{"a", "&", "b"}},
+	{"a '&&'", []string{"a", "&&"}},
+	{"a\"&\"b", []string{"a&b"}},
+	{"a | b || c", []string{"a", "|", "b", "||", "c"}},
+	{"a >> f 2>&1", []string{"a", ">>", "f", "2", ">&", "1"}},
+	{"(a; b)", []string{"(", "a", ";", "b", ")"}},
+}
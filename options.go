@@ -0,0 +1,154 @@
+package shellquote
+
+// Options controls the behavior of Split and the other splitting functions.
+// The zero value splits the same way as the package-level Split.
+type Options struct {
+	// PreserveWhitespace makes Tokens report the runs of separators between
+	// words (including any leading and trailing run) as Whitespace tokens.
+	PreserveWhitespace bool
+
+	// Operators makes unquoted control and redirection operators such as
+	// "|", "&&" and ">>" separate tokens, even when they aren't surrounded
+	// by whitespace. Operators are always matched as long as possible, so
+	// "&>>" is a single operator rather than "&" followed by ">>".
+	Operators bool
+
+	// Comments makes an unquoted "#" at the start of a word begin a comment,
+	// which runs to the end of the line. The comment is discarded, so input
+	// consisting only of a comment contains no words. A "#" inside a word,
+	// as in "a#b", is literal.
+	Comments bool
+
+	// ErrorOnEmpty makes Split return ErrEmptyInput if the input contains no
+	// words at all, such as when it is empty or only whitespace.
+	ErrorOnEmpty bool
+
+	// StripBOM skips a UTF-8 byte order mark at the start of the input, as
+	// prepended by some Windows editors, instead of making it part of the
+	// first word. Offsets reported in tokens are still relative to the
+	// original input.
+	StripBOM bool
+
+	// StripCRInQuotes removes carriage returns from inside quoted strings,
+	// where they would otherwise be literal characters. This helps with
+	// input that went through a CRLF line ending conversion. A \r escape
+	// inside a $'...' string still produces a carriage return.
+	StripCRInQuotes bool
+
+	// IsDelim, if set, decides which characters separate words in place of
+	// the usual space, tab and newline. It is called for each unquoted and
+	// unescaped character with inQuote false, and for each character inside
+	// single or double quotes with inQuote true. A delimiter inside quotes
+	// ends the current word without ending the quoted string, so the next
+	// word carries on inside the quotes. A backslash-escaped delimiter is a
+	// literal part of its word.
+	//
+	// Runs of delimiters between words are skipped, just like runs of
+	// whitespace.
+	IsDelim func(r rune, inQuote bool) bool
+
+	// PreserveEmptyFields makes delimiters other than whitespace work like
+	// the non-whitespace characters of IFS: each of them ends a field, so
+	// two delimiters in a row, or one at the start of the input, result in
+	// an empty word. With IsDelim splitting on ":", "a::b" splits into "a",
+	// "" and "b", while a trailing delimiter doesn't add an empty word. An
+	// escaped delimiter is literal and never ends a field. Whitespace around
+	// the delimiters is still skipped.
+	PreserveEmptyFields bool
+
+	// KeepSingleQuotes keeps the quotes around single-quoted strings in the
+	// words returned, so 'a b' splits into the single word "'a b'" rather
+	// than "a b".
+	KeepSingleQuotes bool
+
+	// KeepDoubleQuotes keeps double-quoted strings in the words returned
+	// exactly as they were written, quotes and backslash-escapes included,
+	// for use with a tool that does its own double quote processing. The
+	// string is still only ended by an unescaped double quote, and nothing
+	// inside it is expanded.
+	KeepDoubleQuotes bool
+
+	// OpaqueSubstitution keeps $(...) command substitutions intact, both
+	// unquoted and inside double quotes. The substitution is copied into the
+	// word verbatim and whitespace, quotes and operators inside it don't
+	// affect splitting. An unclosed substitution results in
+	// ErrUnterminatedSubstitution.
+	//
+	// An unquoted ")" that doesn't close a "(" earlier in the same word
+	// results in ErrUnexpectedClose, as it is most likely left over from a
+	// malformed substitution. If Operators is set as well, parentheses are
+	// operators instead and aren't checked.
+	OpaqueSubstitution bool
+
+	// ANSIC enables bash's $'...' quoting, in which backslash-escapes such
+	// as \n, \t, \x41 and \u00e9 are decoded as in C. Escapes that bash
+	// doesn't recognize are kept as they are, backslash included. That
+	// includes a backslash followed by a newline, which isn't a line
+	// continuation inside $'...'. An unterminated $'...' string results in
+	// UnterminatedSingleQuoteError.
+	ANSIC bool
+
+	// Lookup enables parameter expansion of $name and ${name}, both unquoted
+	// and inside double quotes, using Lookup to find the values of
+	// parameters. An unset parameter expands to nothing.
+	//
+	// Lookup returns a list of values so that $@ and $* can be supported.
+	// $@ (or "$@") expands to one word per value, while $* (or "$*") joins
+	// the values with the first character of IFS into a single word, just
+	// as any other parameter with several values does. IFS itself is found
+	// through Lookup, and if it is unset a space is used.
+	//
+	// The ${name:-word}, ${name:=word}, ${name:+word} and ${name:?word}
+	// forms are supported, with the usual POSIX meaning for a parameter
+	// that is unset or empty. word has its quotes removed and parameters in
+	// it expanded, but it isn't split. Since Lookup can't set parameters,
+	// ${name:=word} substitutes word without assigning it. ${name:?word}
+	// results in a *ParameterError.
+	//
+	// The results of expansion aren't split into fields or matched as
+	// globs, so an unquoted $var that contains spaces still makes up a
+	// single word. However, an unquoted expansion that results in an empty
+	// word is dropped, as is a "$@" with no values.
+	Lookup func(name string) ([]string, bool)
+
+	// ExpandTilde enables tilde expansion. An unquoted "~" at the start of a
+	// word, followed by an optional user name and then a "/" or the end of
+	// the word, is replaced by the home directory that ExpandTilde returns
+	// for the user name, which is empty for a bare "~". If ExpandTilde
+	// returns false, or the tilde isn't at the start of the word, as in
+	// "a~b", or is followed by anything else, as in "~$x", it is left as a
+	// literal "~".
+	ExpandTilde func(user string) (home string, ok bool)
+
+	// MaxOutputLen, if positive, limits the total length in bytes of all the
+	// words produced. Input that exceeds it results in ErrOutputTooLong.
+	// This bounds the memory used for untrusted input, where escapes and
+	// expansions can make the words longer than the input itself.
+	MaxOutputLen int
+
+	// The Warn options enable checks made by Lint. They have no effect on
+	// Split.
+
+	// WarnMixedQuoting warns about a word that uses both single and double
+	// quotes, such as a'b'"c", which is valid but hard to read.
+	WarnMixedQuoting bool
+
+	// WarnEmptyQuoteRun warns about a word containing two or more empty
+	// quoted strings in a row, such as ''"", which is likely to be a
+	// quoting mistake.
+	WarnEmptyQuoteRun bool
+
+	// WarnGlob warns about a word containing an unquoted *, ? or [...],
+	// which the shell would expand as a glob, probably by accident.
+	WarnGlob bool
+
+	// WarnHistoryExpansion warns about a "!" that interactive bash would
+	// treat as history expansion, such as in "!!" or "!foo". This happens
+	// even inside double quotes, so only single quotes or a backslash make
+	// a "!" safe.
+	WarnHistoryExpansion bool
+
+	// autoClose makes the end of input close an unterminated quoted string,
+	// which is then reported as ErrAutoClosed. It is set by SplitLenient.
+	autoClose bool
+}
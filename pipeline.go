@@ -0,0 +1,157 @@
+package shellquote
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrMissingCommand        = errors.New("Missing command")
+	ErrMissingRedirectTarget = errors.New("Missing redirection target")
+	ErrUnexpectedOperator    = errors.New("Unexpected operator")
+)
+
+// Pipeline is a sequence of commands connected by pipes. Pipelines can in
+// turn be joined by the logical operators && and ||, forming a list in which
+// each Pipeline points to the one after it.
+type Pipeline struct {
+	Commands []*SimpleCommand
+	Op       string    // "&&" or "||" joining this pipeline to Next, or ""
+	Next     *Pipeline // the next pipeline in the list, or nil
+}
+
+// SimpleCommand is a single command: a list of words and the redirections
+// that apply to it.
+type SimpleCommand struct {
+	Args      []string
+	Redirects []Redirect
+}
+
+// Redirect is an I/O redirection such as "2> errors.log".
+type Redirect struct {
+	Fd     int    // the file descriptor being redirected, or -1 if not given
+	Op     string // the redirection operator, such as ">" or ">>"
+	Target string // the file name or descriptor, with quotes processed
+}
+
+// redirectOps are the operators that introduce a redirection.
+var redirectOps = map[string]bool{
+	"<": true, ">": true, ">>": true, ">|": true, "<>": true, "<&": true,
+	">&": true, "&>": true, "&>>": true, "<<": true, "<<-": true,
+}
+
+// ParsePipeline parses input as a list of pipelines joined by && and ||,
+// where each pipeline is made up of simple commands joined by | or |&. Words
+// are split as by Split, and redirections are attached to the command they
+// appear in. A |& is treated as a | with an added "2>&1" redirection. The
+// bodies of here-documents aren't read; the Target of a << redirection is
+// just the delimiter.
+//
+// Any other operator, such as ; or (, results in ErrUnexpectedOperator.
+// A missing command, such as before a trailing |, results in
+// ErrMissingCommand, and a redirection without a target results in
+// ErrMissingRedirectTarget. These errors are returned as a *SyntaxError
+// recording where the problem is. Empty input results in ErrEmptyInput.
+func ParsePipeline(input string) (*Pipeline, error) {
+	opts := Options{Operators: true}
+	tokens, err := opts.Tokens(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmptyInput
+	}
+
+	first := &Pipeline{}
+	p := first
+	cmd := &SimpleCommand{}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind == Word {
+			if fd, ok := redirectFd(tokens, i); ok {
+				i++
+				r, err := parseRedirect(tokens, i, fd)
+				if err != nil {
+					return nil, err
+				}
+				cmd.Redirects = append(cmd.Redirects, r)
+				i++
+				continue
+			}
+			cmd.Args = append(cmd.Args, tok.Value)
+			continue
+		}
+
+		switch op := tok.Value; {
+		case redirectOps[op]:
+			r, err := parseRedirect(tokens, i, -1)
+			if err != nil {
+				return nil, err
+			}
+			cmd.Redirects = append(cmd.Redirects, r)
+			i++
+		case op == "|" || op == "|&":
+			if cmd.isEmpty() {
+				return nil, &SyntaxError{tok.Offset, ErrMissingCommand}
+			}
+			if op == "|&" {
+				cmd.Redirects = append(cmd.Redirects, Redirect{2, ">&", "1"})
+			}
+			p.Commands = append(p.Commands, cmd)
+			cmd = &SimpleCommand{}
+		case op == "&&" || op == "||":
+			if cmd.isEmpty() {
+				return nil, &SyntaxError{tok.Offset, ErrMissingCommand}
+			}
+			p.Commands = append(p.Commands, cmd)
+			p.Op = op
+			p.Next = &Pipeline{}
+			p = p.Next
+			cmd = &SimpleCommand{}
+		default:
+			return nil, &SyntaxError{tok.Offset, ErrUnexpectedOperator}
+		}
+	}
+	if cmd.isEmpty() {
+		return nil, &SyntaxError{len(input), ErrMissingCommand}
+	}
+	p.Commands = append(p.Commands, cmd)
+	return first, nil
+}
+
+func (c *SimpleCommand) isEmpty() bool {
+	return len(c.Args) == 0 && len(c.Redirects) == 0
+}
+
+// redirectFd reports whether the word at tokens[i] is the file descriptor
+// number of a redirection, as in "2>file", returning the number if so. It
+// must be unquoted and immediately followed by a redirection operator.
+func redirectFd(tokens []Token, i int) (int, bool) {
+	tok := tokens[i]
+	if tok.Quote != Unquoted || i+1 >= len(tokens) {
+		return 0, false
+	}
+	next := tokens[i+1]
+	if next.Kind != Operator || !redirectOps[next.Value] || next.Offset != tok.Offset+len(tok.Raw) {
+		return 0, false
+	}
+	for j := 0; j < len(tok.Value); j++ {
+		if !isDigit(tok.Value[j]) {
+			return 0, false
+		}
+	}
+	fd, err := strconv.Atoi(tok.Value)
+	if err != nil {
+		return 0, false
+	}
+	return fd, true
+}
+
+// parseRedirect parses the redirection whose operator is at tokens[i].
+func parseRedirect(tokens []Token, i int, fd int) (Redirect, error) {
+	op := tokens[i]
+	if i+1 >= len(tokens) || tokens[i+1].Kind != Word {
+		return Redirect{}, &SyntaxError{op.Offset, ErrMissingRedirectTarget}
+	}
+	return Redirect{fd, op.Value, tokens[i+1].Value}, nil
+}
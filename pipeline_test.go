@@ -0,0 +1,110 @@
+package shellquote
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePipeline(t *testing.T) {
+	for _, elem := range parsePipelineTest {
+		output, err := ParsePipeline(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %s, expected %s", elem.input, dumpPipeline(output), dumpPipeline(elem.output))
+		}
+	}
+}
+
+func TestErrorParsePipeline(t *testing.T) {
+	for _, elem := range errorParsePipelineTest {
+		_, err := ParsePipeline(elem.input)
+		var serr *SyntaxError
+		if !errors.As(err, &serr) || serr.Err != elem.error || serr.Offset != elem.offset {
+			t.Errorf("Input %q, got error %#v, expected %#v at offset %d", elem.input, err, elem.error, elem.offset)
+		}
+	}
+	if _, err := ParsePipeline("  "); err != ErrEmptyInput {
+		t.Errorf("got error %#v, expected %#v", err, ErrEmptyInput)
+	}
+	if _, err := ParsePipeline("echo 'a | b"); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
+
+func dumpPipeline(p *Pipeline) string {
+	s := ""
+	for ; p != nil; p = p.Next {
+		for _, cmd := range p.Commands {
+			s += "{" + Join(cmd.Args...)
+			for _, r := range cmd.Redirects {
+				s += " " + r.Op + r.Target
+			}
+			s += "}"
+		}
+		s += p.Op
+	}
+	return s
+}
+
+var parsePipelineTest = []struct {
+	input  string
+	output *Pipeline
+}{
+	{"grep foo < in | sort -u > out || echo fail", &Pipeline{
+		Commands: []*SimpleCommand{
+			{Args: []string{"grep", "foo"}, Redirects: []Redirect{{-1, "<", "in"}}},
+			{Args: []string{"sort", "-u"}, Redirects: []Redirect{{-1, ">", "out"}}},
+		},
+		Op: "||",
+		Next: &Pipeline{Commands: []*SimpleCommand{
+			{Args: []string{"echo", "fail"}},
+		}},
+	}},
+	{"ls", &Pipeline{Commands: []*SimpleCommand{{Args: []string{"ls"}}}}},
+	{`make 2>errors.log && echo "all done" >>"my log"`, &Pipeline{
+		Commands: []*SimpleCommand{
+			{Args: []string{"make"}, Redirects: []Redirect{{2, ">", "errors.log"}}},
+		},
+		Op: "&&",
+		Next: &Pipeline{Commands: []*SimpleCommand{
+			{Args: []string{"echo", "all done"}, Redirects: []Redirect{{-1, ">>", "my log"}}},
+		}},
+	}},
+	{`echo 2 > f "3">g`, &Pipeline{Commands: []*SimpleCommand{
+		{Args: []string{"echo", "2", "3"}, Redirects: []Redirect{{-1, ">", "f"}, {-1, ">", "g"}}},
+	}}},
+	{"a|&b", &Pipeline{Commands: []*SimpleCommand{
+		{Args: []string{"a"}, Redirects: []Redirect{{2, ">&", "1"}}},
+		{Args: []string{"b"}},
+	}}},
+	{"> empty", &Pipeline{Commands: []*SimpleCommand{
+		{Redirects: []Redirect{{-1, ">", "empty"}}},
+	}}},
+	{"a && b || c", &Pipeline{
+		Commands: []*SimpleCommand{{Args: []string{"a"}}},
+		Op:       "&&",
+		Next: &Pipeline{
+			Commands: []*SimpleCommand{{Args: []string{"b"}}},
+			Op:       "||",
+			Next:     &Pipeline{Commands: []*SimpleCommand{{Args: []string{"c"}}}},
+		},
+	}},
+}
+
+var errorParsePipelineTest = []struct {
+	input  string
+	error  error
+	offset int
+}{
+	{"grep foo |", ErrMissingCommand, 10},
+	{"| grep foo", ErrMissingCommand, 0},
+	{"a | | b", ErrMissingCommand, 4},
+	{"a && || b", ErrMissingCommand, 5},
+	{"a ||", ErrMissingCommand, 4},
+	{"sort >", ErrMissingRedirectTarget, 5},
+	{"sort > | b", ErrMissingRedirectTarget, 5},
+	{"a; b", ErrUnexpectedOperator, 1},
+	{"(a)", ErrUnexpectedOperator, 0},
+}
@@ -2,13 +2,15 @@ package shellquote
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"unicode/utf8"
 )
 
 // Join quotes each argument and joins them with a space.
 // If passed to /bin/sh, the resulting string will be split back into the
-// original arguments.
+// original arguments. An empty argument is written as a pair of single
+// quotes, so that it isn't lost.
 func Join(args ...string) string {
 	var buf bytes.Buffer
 	for i, arg := range args {
@@ -20,6 +22,176 @@ func Join(args ...string) string {
 	return buf.String()
 }
 
+// Part is one piece of the command line built by JoinMixed. Use Arg or Raw to
+// create one.
+type Part struct {
+	value string
+	raw   bool
+}
+
+// Arg returns a Part that JoinMixed quotes like Join quotes its arguments.
+func Arg(arg string) Part {
+	return Part{arg, false}
+}
+
+// Raw returns a Part that JoinMixed writes verbatim. It is meant for
+// fragments that are already correctly quoted, such as a command string built
+// by an earlier call to Join, and must never contain untrusted input.
+func Raw(fragment string) Part {
+	return Part{fragment, true}
+}
+
+// JoinMixed joins parts with a space, quoting each Arg part and writing each
+// Raw part as it is.
+func JoinMixed(parts ...Part) string {
+	var buf bytes.Buffer
+	for i, part := range parts {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+		if part.raw {
+			buf.WriteString(part.value)
+		} else {
+			quote(part.value, &buf)
+		}
+	}
+	return buf.String()
+}
+
+// SimplifyQuoting rewrites a command string with as little quoting as
+// possible, by splitting it and joining the words back together with Join.
+// Quotes around words that don't need them are removed, so `echo "hello"`
+// becomes `echo hello`. Words that do need quoting end up quoted the way Join
+// quotes them, which means single quotes, so `"a b"` becomes `'a b'`. The
+// whitespace between words is normalized to a single space.
+//
+// Any error from splitting input is returned as is.
+func SimplifyQuoting(input string) (string, error) {
+	_, canonical, err := SplitAndCanonical(input)
+	return canonical, err
+}
+
+// SplitAndCanonical splits input like Split, and also returns the canonical
+// form of the command, which is the same as Join(words...). Both are built
+// from a single pass over input.
+func SplitAndCanonical(input string) (words []string, canonical string, err error) {
+	var opts Options
+	var buf bytes.Buffer
+	words = make([]string, 0)
+	err = opts.scan(input, func(tok Token) error {
+		if len(words) > 0 {
+			buf.WriteByte(' ')
+		}
+		words = append(words, tok.Value)
+		quote(tok.Value, &buf)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return words, buf.String(), nil
+}
+
+// JoinTo is like Join, but appends the result to b instead of returning it.
+// Nothing is written if there are no args. Otherwise, if b isn't empty it is
+// separated from the first argument by a space, unless b already ends in a
+// space, tab or newline, in which case the caller's own separator is used.
+func JoinTo(b *strings.Builder, args ...string) {
+	if len(args) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	if s := b.String(); len(s) > 0 && !strings.ContainsRune(splitChars, rune(s[len(s)-1])) {
+		buf.WriteByte(' ')
+	}
+	for i, arg := range args {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+		quote(arg, &buf)
+	}
+	b.Write(buf.Bytes())
+}
+
+var ErrPositionOutOfRange = errors.New("Position out of range")
+
+// InsertArg splits command, inserts arg as the word at index pos, and joins
+// the words back together with Join. A pos equal to the number of words, or
+// any negative pos, appends arg. Any other pos outside the command results in
+// ErrPositionOutOfRange.
+//
+// Since the command is rejoined, its existing words are requoted the way
+// SimplifyQuoting would requote them.
+func InsertArg(command string, pos int, arg string) (string, error) {
+	words, err := Split(command)
+	if err != nil {
+		return "", err
+	}
+	if pos < 0 {
+		pos = len(words)
+	} else if pos > len(words) {
+		return "", ErrPositionOutOfRange
+	}
+	words = append(words[:pos], append([]string{arg}, words[pos:]...)...)
+	return Join(words...), nil
+}
+
+var ErrInvalidName = errors.New("Invalid variable name")
+
+// QuoteAssignment returns a shell variable assignment of value to name, such
+// as for an "env NAME=value cmd" prefix. The value is quoted, but name and
+// the "=" are not, since the shell only recognizes an assignment when they
+// are unquoted. If name isn't a valid shell variable name, ErrInvalidName is
+// returned.
+func QuoteAssignment(name, value string) (string, error) {
+	if !isName(name) {
+		return "", ErrInvalidName
+	}
+	var buf bytes.Buffer
+	buf.WriteString(name)
+	buf.WriteByte('=')
+	quote(value, &buf)
+	return buf.String(), nil
+}
+
+// DefaultExecPlaceholders are the arguments JoinExecTemplate passes through
+// unquoted: find's "{}" placeholder and its "\;" and "+" terminators.
+var DefaultExecPlaceholders = []string{"{}", "\\;", "+"}
+
+// JoinExecTemplate is like Join, except that the placeholders listed in
+// DefaultExecPlaceholders are written verbatim, leaving them intact for
+// commands like find's -exec. A bare ";" argument is escaped as "\;" as
+// usual, which is also what find expects.
+func JoinExecTemplate(args ...string) string {
+	return JoinPlaceholders(DefaultExecPlaceholders, args...)
+}
+
+// JoinPlaceholders is like Join, except that any argument that exactly
+// matches one of placeholders is written verbatim instead of being quoted.
+func JoinPlaceholders(placeholders []string, args ...string) string {
+	var buf bytes.Buffer
+	for i, arg := range args {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+		if isPlaceholder(arg, placeholders) {
+			buf.WriteString(arg)
+		} else {
+			quote(arg, &buf)
+		}
+	}
+	return buf.String()
+}
+
+func isPlaceholder(arg string, placeholders []string) bool {
+	for _, p := range placeholders {
+		if arg == p {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	specialChars      = "\\'\"`${[|&;<>()*?!"
 	extraSpecialChars = " \t\n"
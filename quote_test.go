@@ -1,6 +1,8 @@
 package shellquote
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -13,6 +15,168 @@ func TestSimpleJoin(t *testing.T) {
 	}
 }
 
+func TestJoinExecTemplate(t *testing.T) {
+	for _, elem := range joinExecTemplateTest {
+		output := JoinExecTemplate(elem.input...)
+		if output != elem.output {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestJoinPlaceholders(t *testing.T) {
+	output := JoinPlaceholders([]string{"%"}, "xargs", "-I", "%", "mv", "%", "dir with space/")
+	expected := "xargs -I % mv % 'dir with space/'"
+	if output != expected {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+	output = JoinPlaceholders([]string{"{{name}}"}, "echo", "{{name}}", "{}")
+	expected = "echo {{name}} \\{}"
+	if output != expected {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+}
+
+func TestJoinMixed(t *testing.T) {
+	sub := Join("grep", "-v", "it's")
+	output := JoinMixed(Arg("ssh"), Arg("my host"), Raw(sub), Raw("|"), Arg("wc"), Arg(""))
+	expected := `ssh 'my host' grep -v it\'s | wc ''`
+	if output != expected {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+	if output := JoinMixed(); output != "" {
+		t.Errorf("got %q for no parts", output)
+	}
+}
+
+func TestJoinTo(t *testing.T) {
+	groups := [][]string{
+		{"find", "my dir"},
+		{},
+		{"-name", "$HOME/x.go"},
+		{"-exec", "it's", ""},
+	}
+	var b strings.Builder
+	var all []string
+	for _, group := range groups {
+		JoinTo(&b, group...)
+		all = append(all, group...)
+	}
+	output, err := Split(b.String())
+	if err != nil {
+		t.Fatalf("Input %q, got error %#v", b.String(), err)
+	}
+	if !reflect.DeepEqual(output, all) {
+		t.Errorf("Input %q, got %q, expected %q", b.String(), output, all)
+	}
+
+	b.Reset()
+	b.WriteString("find")
+	JoinTo(&b)
+	if b.String() != "find" {
+		t.Errorf("got %q after joining no args, expected %q", b.String(), "find")
+	}
+	JoinTo(&b, ".")
+	if b.String() != "find ." {
+		t.Errorf("got %q, expected %q", b.String(), "find .")
+	}
+
+	b.Reset()
+	b.WriteString("sudo\t")
+	JoinTo(&b, "ls", "a b")
+	if expected := "sudo\tls 'a b'"; b.String() != expected {
+		t.Errorf("got %q, expected %q", b.String(), expected)
+	}
+}
+
+func TestInsertArg(t *testing.T) {
+	for _, elem := range insertArgTest {
+		output, err := InsertArg(elem.command, elem.pos, elem.arg)
+		if err != nil {
+			t.Errorf("Input %q at %d, got error %#v", elem.command, elem.pos, err)
+		} else if output != elem.output {
+			t.Errorf("Input %q at %d, got %q, expected %q", elem.command, elem.pos, output, elem.output)
+		}
+	}
+	for _, pos := range []int{4, 100} {
+		if _, err := InsertArg("ls -l x", pos, "y"); err != ErrPositionOutOfRange {
+			t.Errorf("Position %d, got error %#v, expected %#v", pos, err, ErrPositionOutOfRange)
+		}
+	}
+	if _, err := InsertArg("ls 'x", 0, "y"); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedSingleQuoteError", err)
+	}
+}
+
+func TestQuoteAssignment(t *testing.T) {
+	for _, elem := range quoteAssignmentTest {
+		output, err := QuoteAssignment(elem.name, elem.value)
+		if err != nil {
+			t.Errorf("Input %q=%q, got error %#v", elem.name, elem.value, err)
+		} else if output != elem.output {
+			t.Errorf("Input %q=%q, got %q, expected %q", elem.name, elem.value, output, elem.output)
+		}
+	}
+	for _, name := range []string{"", "1ABC", "A-B", "A B", "A=B", "$A", "ÄB"} {
+		if _, err := QuoteAssignment(name, "value"); err != ErrInvalidName {
+			t.Errorf("Input %q, got error %#v, expected %#v", name, err, ErrInvalidName)
+		}
+	}
+}
+
+func TestSimplifyQuoting(t *testing.T) {
+	for _, elem := range simplifyQuotingTest {
+		output, err := SimplifyQuoting(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if output != elem.output {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+	if _, err := SimplifyQuoting(`echo "oops`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+}
+
+func TestSplitAndCanonical(t *testing.T) {
+	inputs := []string{
+		`echo "hello" 'world' "a b"`,
+		`  git   commit -m "it's done"  `,
+		`a\ b "" '$x' "*"`,
+		`find . -exec rm {} \;`,
+		`grep a\|b f`,
+		``,
+	}
+	for _, input := range inputs {
+		words, canonical, err := SplitAndCanonical(input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", input, err)
+			continue
+		}
+		expected, _ := Split(input)
+		if !reflect.DeepEqual(words, expected) {
+			t.Errorf("Input %q, got words %q, expected %q", input, words, expected)
+		}
+		if expected := Join(expected...); canonical != expected {
+			t.Errorf("Input %q, got canonical %q, expected %q", input, canonical, expected)
+		}
+	}
+	if _, _, err := SplitAndCanonical(`a 'b`); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedSingleQuoteError", err)
+	}
+}
+
+var benchJoinArgs = []string{
+	"git", "commit", "-m", "fix the thing", "--author=A. U. Thor <a@example.com>",
+	"--", "file one.go", "it's", "$HOME", "plain",
+}
+
+func BenchmarkJoin(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Join(benchJoinArgs...)
+	}
+}
+
 var simpleJoinTest = []struct {
 	input  []string
 	output string
@@ -25,7 +189,60 @@ var simpleJoinTest = []struct {
 	{[]string{"~user", "u~ser", " ~user", "!~user"}, "\\~user u~ser ' ~user' \\!~user"},
 	{[]string{"foo*", "M{ovies,usic}", "ab[cd]", "%3"}, "foo\\* M\\{ovies,usic} ab\\[cd] %3"},
 	{[]string{"one", "", "three"}, "one '' three"},
+	{[]string{""}, "''"},
+	{[]string{"a", "", "b"}, "a '' b"},
+	{[]string{"", ""}, "'' ''"},
 	{[]string{"some(parentheses)"}, "some\\(parentheses\\)"},
 	{[]string{"$some_ot~her_)spe!cial_*_characters"}, "\\$some_ot~her_\\)spe\\!cial_\\*_characters"},
 	{[]string{"' "}, "\\'' '"},
 }
+
+var joinExecTemplateTest = []struct {
+	input  []string
+	output string
+}{
+	{[]string{"find", ".", "-exec", "grep", "a b", "{}", ";"}, "find . -exec grep 'a b' {} \\;"},
+	{[]string{"find", ".", "-exec", "rm", "{}", "\\;"}, "find . -exec rm {} \\;"},
+	{[]string{"find", ".", "-name", "*.go", "-exec", "gofmt", "-l", "{}", "+"}, "find . -name \\*.go -exec gofmt -l {} +"},
+	{[]string{"echo", "{} x"}, "echo '{} x'"},
+}
+
+var quoteAssignmentTest = []struct {
+	name   string
+	value  string
+	output string
+}{
+	{"GREETING", "hello world", "GREETING='hello world'"},
+	{"_x1", "plain", "_x1=plain"},
+	{"EMPTY", "", "EMPTY=''"},
+	{"CMD", "$(rm -rf /); echo", "CMD='$(rm -rf /); echo'"},
+	{"HOME", "~user", "HOME=\\~user"},
+}
+
+var simplifyQuotingTest = []struct {
+	input  string
+	output string
+}{
+	{`echo "hello" 'world' "a b"`, `echo hello world 'a b'`},
+	{`  ls   -l  `, `ls -l`},
+	{`echo "$HOME" '*' ""`, `echo \$HOME \* ''`},
+	{`a" "b c\ d`, `'a b' 'c d'`},
+	{`find . -exec rm {} \;`, `find . -exec rm \{} \;`},
+	{`grep a\|b f`, `grep a\|b f`},
+	{``, ``},
+}
+
+var insertArgTest = []struct {
+	command string
+	pos     int
+	arg     string
+	output  string
+}{
+	{`ls -l "my dir"`, 0, "sudo", `sudo ls -l 'my dir'`},
+	{`ls -l "my dir"`, 2, "a b", `ls -l 'a b' 'my dir'`},
+	{`ls -l "my dir"`, 3, "it's", `ls -l 'my dir' it\'s`},
+	{`ls -l "my dir"`, -1, "", `ls -l 'my dir' ''`},
+	{``, 0, "x", `x`},
+	{`grep a\|b f`, 0, "sudo", `sudo grep a\|b f`},
+	{`find . -exec rm {} \;`, 4, "-f", `find . -exec rm -f \{} \;`},
+}
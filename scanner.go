@@ -0,0 +1,112 @@
+package shellquote
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Scanner reads words from an io.Reader, using the same rules as Split. Only
+// as much of the input as is needed to find the next word is kept in memory.
+type Scanner struct {
+	s   *bufio.Scanner
+	buf bytes.Buffer
+}
+
+// NewScanner returns a new Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	sc := &Scanner{s: bufio.NewScanner(r)}
+	sc.s.Split(sc.split)
+	return sc
+}
+
+// Buffer sets the initial buffer to use when scanning and the maximum size of
+// buffer that may be allocated, as for bufio.Scanner. A word (including any
+// quoted newlines) longer than max results in bufio.ErrTooLong. Buffer panics
+// if it is called after scanning has started.
+func (s *Scanner) Buffer(buf []byte, max int) {
+	s.s.Buffer(buf, max)
+}
+
+// Scan advances the Scanner to the next word, which will then be available
+// through the Text method. It returns false when there are no more words,
+// either because the end of the input was reached or because of an error.
+func (s *Scanner) Scan() bool {
+	return s.s.Scan()
+}
+
+// Text returns the most recent word found by a call to Scan.
+func (s *Scanner) Text() string {
+	return s.s.Text()
+}
+
+// Err returns the first error encountered by the Scanner, such as
+// UnterminatedSingleQuoteError. It returns nil if the end of the input was
+// reached without error.
+func (s *Scanner) Err() error {
+	return s.s.Err()
+}
+
+// ForEach calls fn with each remaining word, in order. Words are only read
+// as fn consumes them, so a slow fn slows down the reading of the input. If
+// fn returns an error, ForEach stops and returns that error. Otherwise it
+// returns the Scanner's error, if any.
+func (s *Scanner) ForEach(fn func(word string) error) error {
+	for s.Scan() {
+		if err := fn(s.Text()); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// split is a bufio.SplitFunc that returns the next word in data. If the word
+// might continue past the end of data, it asks for more.
+func (s *Scanner) split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	input := string(data)
+
+	// skip any splitChars at the start
+	start := input
+	for len(input) > 0 {
+		c, l := utf8.DecodeRuneInString(input)
+		if strings.ContainsRune(splitChars, c) {
+			input = input[l:]
+			continue
+		} else if c == escapeChar {
+			next := input[l:]
+			if len(next) == 0 {
+				if atEOF {
+					return 0, nil, UnterminatedEscapeError
+				}
+				break
+			}
+			c2, l2 := utf8.DecodeRuneInString(next)
+			if c2 == '\n' {
+				input = next[l2:]
+				continue
+			}
+		}
+		break
+	}
+	skipped := len(start) - len(input)
+	if len(input) == 0 || (!atEOF && input == string(escapeChar)) {
+		return skipped, nil, nil
+	}
+
+	var opts Options
+	fields, _, rest, err := opts.splitWord(input, &s.buf, -1)
+	if !atEOF && (err != nil || len(rest) == 0) {
+		// the word may continue in data we haven't read yet
+		return skipped, nil, nil
+	}
+	if err != nil {
+		if serr, ok := err.(*SyntaxError); ok {
+			err = serr.Err
+		}
+		return 0, nil, err
+	}
+	// without expansion, a word is always exactly one field
+	return len(data) - len(rest), append([]byte{}, fields[0]...), nil
+}
@@ -0,0 +1,78 @@
+package shellquote
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanner(t *testing.T) {
+	input := "one 'two three' \"four\\\nfive\"\n  six\\\n seven ''"
+	expected := []string{"one", "two three", "fourfive", "six", "seven", ""}
+
+	// read one byte at a time, to exercise words split across reads
+	sc := NewScanner(iotest.OneByteReader(strings.NewReader(input)))
+	var output []string
+	for sc.Scan() {
+		output = append(output, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	for _, elem := range errorSplitTest {
+		sc := NewScanner(iotest.OneByteReader(strings.NewReader(elem.input)))
+		for sc.Scan() {
+		}
+		if err := sc.Err(); err != elem.error {
+			t.Errorf("Input %q, got error %#v, expected error %#v", elem.input, err, elem.error)
+		}
+	}
+}
+
+func TestScannerForEach(t *testing.T) {
+	sc := NewScanner(strings.NewReader("a b 'c d' e"))
+	var output []string
+	err := sc.ForEach(func(word string) error {
+		output = append(output, word)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []string{"a", "b", "c d", "e"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	stop := errors.New("stop")
+	sc = NewScanner(strings.NewReader("a b stop c d"))
+	output = nil
+	err = sc.ForEach(func(word string) error {
+		if word == "stop" {
+			return stop
+		}
+		output = append(output, word)
+		return nil
+	})
+	if err != stop {
+		t.Errorf("got error %#v, expected %#v", err, stop)
+	}
+	expected = []string{"a", "b"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	sc = NewScanner(strings.NewReader("a 'b"))
+	err = sc.ForEach(func(word string) error { return nil })
+	if err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
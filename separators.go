@@ -0,0 +1,22 @@
+package shellquote
+
+// SplitWithSeparators splits input like Split, but each returned element is
+// a word followed by the run of separators that came after it in input. The
+// separators are copied verbatim, including any backslash-escaped newlines.
+// Separators before the first word are discarded.
+//
+// Concatenating the elements reproduces the spacing of input exactly, with
+// only quote and escape removal applied to the words themselves.
+func SplitWithSeparators(input string) ([]string, error) {
+	opts := Options{PreserveWhitespace: true}
+	words := make([]string, 0)
+	err := opts.scan(input, func(tok Token) error {
+		if tok.Kind == Word {
+			words = append(words, tok.Value)
+		} else if len(words) > 0 {
+			words[len(words)-1] += tok.Raw
+		}
+		return nil
+	})
+	return words, err
+}
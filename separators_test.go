@@ -0,0 +1,35 @@
+package shellquote
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitWithSeparators(t *testing.T) {
+	for _, elem := range splitWithSeparatorsTest {
+		output, err := SplitWithSeparators(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+		if joined := strings.Join(output, ""); joined != elem.joined {
+			t.Errorf("Input %q, reconstructed %q, expected %q", elem.input, joined, elem.joined)
+		}
+	}
+}
+
+var splitWithSeparatorsTest = []struct {
+	input  string
+	output []string
+	joined string
+}{
+	{"", []string{}, ""},
+	{"a  b\tc", []string{"a  ", "b\t", "c"}, "a  b\tc"},
+	{"  lead trail  ", []string{"lead ", "trail  "}, "lead trail  "},
+	{"one   'two  three'\n four", []string{"one   ", "two  three\n ", "four"}, "one   two  three\n four"},
+	{"a \\\n b", []string{"a \\\n ", "b"}, "a \\\n b"},
+}
@@ -0,0 +1,76 @@
+package shellquote
+
+import "strings"
+
+// Shell identifies a shell dialect.
+type Shell int
+
+const (
+	// Sh is the POSIX shell.
+	Sh Shell = iota
+	// Bash is the GNU Bourne-Again shell.
+	Bash
+	// PowerShell is Microsoft PowerShell.
+	PowerShell
+	// Cmd is the Windows command interpreter, cmd.exe.
+	Cmd
+)
+
+// expandChars are the characters that cause a POSIX shell to change an
+// unquoted word: whitespace splits it, quotes and backslashes are removed, $
+// and ` introduce expansions, *, ? and [ are globs, and the rest are
+// operators.
+const expandChars = " \t\n\\'\"$`*?[|&;<>()"
+
+// WouldExpand reports whether shell would alter arg if it were passed
+// unquoted, whether through expansion, field splitting, globbing, quote
+// removal or by parsing part of it as an operator. An empty arg is also
+// reported, since it would vanish entirely.
+//
+// WouldExpand only knows the rules of POSIX shells, so PowerShell and Cmd
+// are treated the same as Sh.
+//
+// For Bash, history expansion with "!" and brace expansion such as "{a,b}"
+// are also taken into account.
+func WouldExpand(shell Shell, arg string) bool {
+	if arg == "" {
+		return true
+	}
+	if arg[0] == '~' || arg[0] == '#' {
+		// tilde expansion and comments only happen at the start of a word
+		return true
+	}
+	if strings.ContainsAny(arg, expandChars) {
+		return true
+	}
+	if shell == Bash {
+		if strings.ContainsRune(arg, '!') || hasBraceExpansion(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBraceExpansion reports whether word contains a brace expression that
+// bash would expand, such as "{a,b}" or "{1..3}".
+func hasBraceExpansion(word string) bool {
+	for {
+		i := strings.IndexByte(word, '{')
+		if i == -1 {
+			return false
+		}
+		word = word[i+1:]
+		j := strings.IndexByte(word, '}')
+		if j == -1 {
+			return false
+		}
+		body := word[:j]
+		if k := strings.LastIndexByte(body, '{'); k != -1 {
+			// the innermost brace expression is the one that matters
+			body = body[k+1:]
+		}
+		if strings.Contains(body, ",") || strings.Contains(body, "..") {
+			return true
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package shellquote
+
+import (
+	"testing"
+)
+
+func TestWouldExpand(t *testing.T) {
+	for _, elem := range wouldExpandTest {
+		if got := WouldExpand(elem.shell, elem.arg); got != elem.expand {
+			t.Errorf("Shell %v, arg %q, got %v, expected %v", elem.shell, elem.arg, got, elem.expand)
+		}
+	}
+}
+
+var wouldExpandTest = []struct {
+	shell  Shell
+	arg    string
+	expand bool
+}{
+	{Bash, "plain", false},
+	{Bash, "path/to/file.txt", false},
+	{Bash, "--flag=value", false},
+	{Bash, "a~b", false},
+	{Bash, "", true},
+	{Bash, "$HOME", true},
+	{Bash, "${var}", true},
+	{Bash, "`date`", true},
+	{Bash, "*.go", true},
+	{Bash, "file?", true},
+	{Bash, "[ab]", true},
+	{Bash, "hello world", true},
+	{Bash, "tab\there", true},
+	{Bash, "~/x", true},
+	{Bash, "#comment", true},
+	{Bash, "it's", true},
+	{Bash, "a|b", true},
+	{Bash, "a;b", true},
+	{Bash, "wow!", true},
+	{Bash, "{a,b}", true},
+	{Bash, "x{1..3}", true},
+	{Bash, "{a}", false},
+	{Bash, "{{a,b}", true},
+	{Sh, "plain", false},
+	{Sh, "$HOME", true},
+	{Sh, "wow!", false},
+	{Sh, "{a,b}", false},
+}
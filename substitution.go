@@ -0,0 +1,80 @@
+package shellquote
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnterminatedSubstitution = errors.New("Unterminated command substitution")
+	ErrUnexpectedClose          = errors.New("Unexpected closing parenthesis")
+)
+
+// substitutionLen returns the length of the rest of a $(...) command
+// substitution, including the closing paren. The input starts just after the
+// opening "$(". Nested parens and quoted strings inside the substitution are
+// balanced, so a ")" inside quotes doesn't close it.
+func substitutionLen(input string) (int, error) {
+	depth := 1
+	cur := input
+	for len(cur) > 0 {
+		c, l := utf8.DecodeRuneInString(cur)
+		cur = cur[l:]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return len(input) - len(cur), nil
+			}
+		case escapeChar:
+			if len(cur) > 0 {
+				_, l2 := utf8.DecodeRuneInString(cur)
+				cur = cur[l2:]
+			}
+		case singleChar:
+			i := strings.IndexRune(cur, singleChar)
+			if i == -1 {
+				return 0, ErrUnterminatedSubstitution
+			}
+			cur = cur[i+1:]
+		case doubleChar:
+			n, err := doubleQuotedLen(cur)
+			if err != nil {
+				return 0, err
+			}
+			cur = cur[n:]
+		}
+	}
+	return 0, ErrUnterminatedSubstitution
+}
+
+// doubleQuotedLen returns the length of the rest of a double-quoted string
+// inside a command substitution, including the closing quote.
+func doubleQuotedLen(input string) (int, error) {
+	cur := input
+	for len(cur) > 0 {
+		c, l := utf8.DecodeRuneInString(cur)
+		cur = cur[l:]
+		switch c {
+		case doubleChar:
+			return len(input) - len(cur), nil
+		case escapeChar:
+			if len(cur) > 0 {
+				_, l2 := utf8.DecodeRuneInString(cur)
+				cur = cur[l2:]
+			}
+		case '$':
+			if strings.HasPrefix(cur, "(") {
+				n, err := substitutionLen(cur[1:])
+				if err != nil {
+					return 0, err
+				}
+				cur = cur[1+n:]
+			}
+		}
+	}
+	return 0, ErrUnterminatedSubstitution
+}
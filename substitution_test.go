@@ -0,0 +1,85 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpaqueSubstitution(t *testing.T) {
+	opts := Options{OpaqueSubstitution: true}
+	for _, elem := range opaqueSubstitutionTest {
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestErrorOpaqueSubstitution(t *testing.T) {
+	opts := Options{OpaqueSubstitution: true}
+	for _, elem := range errorOpaqueSubstitutionTest {
+		_, err := opts.Split(elem.input)
+		if err != elem.error {
+			t.Errorf("Input %q, got error %#v, expected error %#v", elem.input, err, elem.error)
+		}
+	}
+}
+
+var opaqueSubstitutionTest = []struct {
+	input  string
+	output []string
+}{
+	{`echo "result: $(echo hi)"`, []string{"echo", "result: $(echo hi)"}},
+	{`echo $(echo hi) there`, []string{"echo", "$(echo hi)", "there"}},
+	{`a$(b c)d`, []string{"a$(b c)d"}},
+	{`"$(echo (nested) parens)"`, []string{"$(echo (nested) parens)"}},
+	{`"$(echo ")" ')')"`, []string{`$(echo ")" ')')`}},
+	{`"outer $(echo "inner $(echo deep)")"`, []string{`outer $(echo "inner $(echo deep)")`}},
+	{`"$(echo \))"`, []string{`$(echo \))`}},
+	{`"cost: $5"`, []string{"cost: $5"}},
+}
+
+var errorOpaqueSubstitutionTest = []struct {
+	input string
+	error error
+}{
+	{`echo "$(echo hi"`, ErrUnterminatedSubstitution},
+	{`echo $(echo hi`, ErrUnterminatedSubstitution},
+	{`"$(echo ')"`, ErrUnterminatedSubstitution},
+	{`"$(echo (a)"`, ErrUnterminatedSubstitution},
+	{`echo )`, ErrUnexpectedClose},
+	{`echo $(a))`, ErrUnexpectedClose},
+	{`f(x))`, ErrUnexpectedClose},
+}
+
+func TestUnexpectedCloseOffset(t *testing.T) {
+	opts := Options{OpaqueSubstitution: true}
+	for _, elem := range []struct {
+		input  string
+		offset int
+	}{
+		{`echo )`, 5},
+		{`echo "a)" $(b)c)`, 15},
+	} {
+		err := opts.scanTokens(elem.input, func(Token) error { return nil })
+		serr, ok := err.(*SyntaxError)
+		if !ok || serr.Err != ErrUnexpectedClose || serr.Offset != elem.offset {
+			t.Errorf("Input %q, got error %#v, expected ErrUnexpectedClose at offset %d", elem.input, err, elem.offset)
+		}
+	}
+	if output := opts.FormatError("echo )", ErrUnexpectedClose); output != "Unexpected closing parenthesis\necho )\n     ^" {
+		t.Errorf("got %q", output)
+	}
+
+	for _, input := range []string{`echo ")" ')' \) f(x)`, `echo $(a) (b)`} {
+		if _, err := opts.Split(input); err != nil {
+			t.Errorf("Input %q, got error %#v", input, err)
+		}
+	}
+	opts.Operators = true
+	if _, err := opts.Split("(a) )"); err != nil {
+		t.Errorf("got error %#v with Operators", err)
+	}
+}
@@ -0,0 +1,113 @@
+package shellquote
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+var ErrMissingArgument = errors.New("Missing template argument")
+
+// quoteContext is the kind of quoting a template placeholder appears in.
+type quoteContext int
+
+const (
+	contextNone quoteContext = iota
+	contextSingle
+	contextDouble
+)
+
+// templatePart is a literal piece of a template followed by an optional
+// placeholder.
+type templatePart struct {
+	literal string
+	arg     int // the argument index, or -1 for no placeholder
+	context quoteContext
+}
+
+// Tmpl is a parsed command template, created by Template.
+type Tmpl struct {
+	parts []templatePart
+}
+
+// Template parses format as a shell command containing numbered
+// placeholders {0}, {1} and so on, which Render replaces with arguments.
+// Placeholders are recognized anywhere, including inside single or double
+// quotes, except when the "{" is backslash-escaped. The format must itself
+// be valid for Split; otherwise the error from Split is returned.
+func Template(format string) (*Tmpl, error) {
+	if _, err := Split(format); err != nil {
+		return nil, err
+	}
+	t := &Tmpl{}
+	context := contextNone
+	start := 0
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '{':
+			j := i + 1
+			for j < len(format) && isDigit(format[j]) {
+				j++
+			}
+			if j > i+1 && j < len(format) && format[j] == '}' {
+				n, _ := parseDigits(format[i+1:j], 10, j-i-1)
+				t.parts = append(t.parts, templatePart{format[start:i], n, context})
+				start = j + 1
+				i = j
+			}
+		case c == '\\' && context != contextSingle:
+			i++ // skip the escaped character
+		case c == '\'' && context != contextDouble:
+			if context == contextSingle {
+				context = contextNone
+			} else {
+				context = contextSingle
+			}
+		case c == '"' && context != contextSingle:
+			if context == contextDouble {
+				context = contextNone
+			} else {
+				context = contextDouble
+			}
+		}
+	}
+	t.parts = append(t.parts, templatePart{format[start:], -1, contextNone})
+	return t, nil
+}
+
+// Render returns the template with each placeholder replaced by the
+// corresponding argument, quoted so that the shell sees it literally. Inside
+// single or double quotes, the argument is escaped to fit within them, so
+// the quoting of the template itself is preserved. If a placeholder has no
+// corresponding argument, ErrMissingArgument is returned.
+//
+// Note that bash's history expansion of "!" can't be prevented inside double
+// quotes, though it only happens in interactive shells.
+func (t *Tmpl) Render(args ...string) (string, error) {
+	var buf bytes.Buffer
+	for _, part := range t.parts {
+		buf.WriteString(part.literal)
+		if part.arg < 0 {
+			continue
+		}
+		if part.arg >= len(args) {
+			return "", ErrMissingArgument
+		}
+		arg := args[part.arg]
+		switch part.context {
+		case contextNone:
+			quote(arg, &buf)
+		case contextSingle:
+			buf.WriteString(strings.ReplaceAll(arg, "'", `'\''`))
+		case contextDouble:
+			for _, c := range arg {
+				if strings.ContainsRune("$`\"\\", c) {
+					buf.WriteByte('\\')
+				}
+				buf.WriteRune(c)
+			}
+		}
+	}
+	return buf.String(), nil
+}
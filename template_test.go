@@ -0,0 +1,57 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	for _, elem := range templateTest {
+		tmpl, err := Template(elem.format)
+		if err != nil {
+			t.Errorf("Format %q, got error %#v", elem.format, err)
+			continue
+		}
+		output, err := tmpl.Render(elem.args...)
+		if err != nil {
+			t.Errorf("Format %q, got error %#v", elem.format, err)
+			continue
+		}
+		if output != elem.output {
+			t.Errorf("Format %q, args %q, got %q, expected %q", elem.format, elem.args, output, elem.output)
+		}
+		words, err := Split(output)
+		if err != nil {
+			t.Errorf("Format %q, splitting %q got error %#v", elem.format, output, err)
+		} else if !reflect.DeepEqual(words, elem.words) {
+			t.Errorf("Format %q, %q split into %q, expected %q", elem.format, output, words, elem.words)
+		}
+	}
+}
+
+func TestErrorTemplate(t *testing.T) {
+	if _, err := Template("echo '{0}"); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+	tmpl, err := Template("cp {0} {1}")
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if _, err := tmpl.Render("only one"); err != ErrMissingArgument {
+		t.Errorf("got error %#v, expected %#v", err, ErrMissingArgument)
+	}
+}
+
+var templateTest = []struct {
+	format string
+	args   []string
+	output string
+	words  []string
+}{
+	{"cp {0} {1}", []string{"my file", "$dest"}, `cp 'my file' \$dest`, []string{"cp", "my file", "$dest"}},
+	{"echo 'hello {0}!'", []string{"it's me"}, `echo 'hello it'\''s me!'`, []string{"echo", "hello it's me!"}},
+	{`echo "hello {0}!"`, []string{"$USER `id` \"x\""}, `echo "hello \$USER \` + "`id\\`" + ` \"x\"!"`, []string{"echo", "hello $USER `id` \"x\"!"}},
+	{"run --name={0} {1}{0}", []string{"a b", ""}, "run --name='a b' '''a b'", []string{"run", "--name=a b", "a b"}},
+	{`echo \{0} '{x}' {}`, []string{"unused"}, `echo \{0} '{x}' {}`, []string{"echo", "{0}", "{x}", "{}"}},
+	{`echo "{1}" '{10}'`, []string{"a", "b\\c", "", "", "", "", "", "", "", "", "it's"}, `echo "b\\c" 'it'\''s'`, []string{"echo", `b\c`, "it's"}},
+}
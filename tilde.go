@@ -0,0 +1,34 @@
+package shellquote
+
+import "unicode/utf8"
+
+// expandTilde performs tilde expansion on a word that starts with input,
+// returning the expanded prefix and the number of bytes of input it
+// replaces. n is 0 if the word doesn't start with a tilde prefix that
+// o.ExpandTilde recognizes.
+func (o *Options) expandTilde(input string) (value string, n int) {
+	if len(input) == 0 || input[0] != '~' {
+		return "", 0
+	}
+	i := 1
+	for i < len(input) && isUserChar(input[i]) {
+		i++
+	}
+	if i < len(input) && input[i] != '/' {
+		// the prefix must end at a slash or the end of the word
+		c, _ := utf8.DecodeRuneInString(input[i:])
+		if !o.isDelim(c, false) && !(o.Operators && matchOperator(input[i:]) != "") {
+			return "", 0
+		}
+	}
+	value, ok := o.ExpandTilde(input[1:i])
+	if !ok {
+		return "", 0
+	}
+	return value, i
+}
+
+// isUserChar reports whether c can be part of a user name in a tilde prefix.
+func isUserChar(c byte) bool {
+	return isNameChar(c) || c == '.' || c == '-'
+}
@@ -0,0 +1,44 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func homeDirs(user string) (string, bool) {
+	switch user {
+	case "":
+		return "/home/me", true
+	case "root":
+		return "/root", true
+	}
+	return "", false
+}
+
+func TestExpandTilde(t *testing.T) {
+	for _, elem := range expandTildeTest {
+		opts := elem.opts
+		opts.ExpandTilde = homeDirs
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+var expandTildeTest = []struct {
+	opts   Options
+	input  string
+	output []string
+}{
+	{Options{}, "~", []string{"/home/me"}},
+	{Options{}, "~/x ~root/y ~root", []string{"/home/me/x", "/root/y", "/root"}},
+	{Options{}, "a~b a~ x/~", []string{"a~b", "a~", "x/~"}},
+	{Options{}, "~nonexistent ~nonexistent/x", []string{"~nonexistent", "~nonexistent/x"}},
+	{Options{}, `~$x ~+ ~"/x"`, []string{"~$x", "~+", "~/x"}},
+	{Options{}, `"~" '~/x' \~`, []string{"~", "~/x", "~"}},
+	{Options{}, "cd ~/a~b", []string{"cd", "/home/me/a~b"}},
+	{Options{Operators: true}, "~>f ~root|x", []string{"/home/me", ">", "f", "/root", "|", "x"}},
+}
@@ -0,0 +1,163 @@
+package shellquote
+
+import "unicode/utf8"
+
+// TokenKind identifies what a Token represents.
+type TokenKind int
+
+const (
+	// Word is a shell word. Its Value has had quotes and escapes processed.
+	Word TokenKind = iota
+	// Whitespace is a run of separators between words, including any
+	// backslash-escaped newlines. Its Value is the same as its Raw text.
+	Whitespace
+	// Operator is a control or redirection operator, reported only when
+	// Options.Operators is set.
+	Operator
+	// Keyword is an unquoted "{" or "}" at the start of a command, where
+	// they are reserved words, as in a function definition. Keywords are
+	// reported only when Options.Operators is set, and are words otherwise.
+	Keyword
+)
+
+// QuoteKind is a set of flags recording which quoting mechanisms were used
+// to form a word. A word written without any quoting has the kind Unquoted.
+type QuoteKind uint8
+
+// Unquoted is the QuoteKind of a word that contains no quoting at all.
+const Unquoted QuoteKind = 0
+
+const (
+	// SingleQuoted is set if any part of the word was single-quoted.
+	SingleQuoted QuoteKind = 1 << iota
+	// DoubleQuoted is set if any part of the word was double-quoted.
+	DoubleQuoted
+	// Escaped is set if the word contains a backslash-escape outside of
+	// any quotes.
+	Escaped
+	// ANSICQuoted is set if any part of the word was a $'...' string.
+	ANSICQuoted
+)
+
+// Token is a single lexical element of an input string.
+type Token struct {
+	Kind   TokenKind
+	Value  string    // the processed value
+	Raw    string    // the verbatim source text
+	Offset int       // byte offset of Raw within the input
+	Quote  QuoteKind // how a Word was quoted
+}
+
+// Tokens splits input into tokens using the same rules as Split.
+//
+// If o.PreserveWhitespace is set, the separators are reported as well, so
+// concatenating the Raw field of every token reproduces input exactly.
+func (o *Options) Tokens(input string) (tokens []Token, err error) {
+	tokens = make([]Token, 0)
+	err = o.scan(input, func(tok Token) error {
+		tokens = append(tokens, tok)
+		return nil
+	})
+	return
+}
+
+// RawWords splits input like Split, but returns each word exactly as it was
+// written, with its quotes and backslash-escapes intact. Joining the words
+// with spaces results in a command that splits into the same words as input,
+// which is useful for passing a command on to another shell unchanged.
+func RawWords(input string) ([]string, error) {
+	var opts Options
+	words := make([]string, 0)
+	err := opts.scan(input, func(tok Token) error {
+		words = append(words, tok.Raw)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// SplitKinds splits input like Split, and also returns how each word was
+// quoted. kinds[i] is the QuoteKind of words[i].
+func SplitKinds(input string) (words []string, kinds []QuoteKind, err error) {
+	var opts Options
+	words = make([]string, 0)
+	kinds = make([]QuoteKind, 0)
+	err = opts.scan(input, func(tok Token) error {
+		words = append(words, tok.Value)
+		kinds = append(kinds, tok.Quote)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return words, kinds, nil
+}
+
+// Stats counts the words of a command by how they were quoted. A word that
+// mixes quoting mechanisms, such as a'b'"c", is counted once for each of
+// them.
+type Stats struct {
+	Unquoted     int
+	SingleQuoted int
+	DoubleQuoted int
+	Escaped      int
+}
+
+// SplitStats splits input like Split, and also counts the words by how they
+// were quoted.
+func SplitStats(input string) (words []string, stats Stats, err error) {
+	var opts Options
+	words = make([]string, 0)
+	err = opts.scan(input, func(tok Token) error {
+		words = append(words, tok.Value)
+		if tok.Quote == Unquoted {
+			stats.Unquoted++
+		}
+		if tok.Quote&SingleQuoted != 0 {
+			stats.SingleQuoted++
+		}
+		if tok.Quote&DoubleQuoted != 0 {
+			stats.DoubleQuoted++
+		}
+		if tok.Quote&Escaped != 0 {
+			stats.Escaped++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Stats{}, err
+	}
+	return words, stats, nil
+}
+
+// WordInfo describes a word found by SplitDetailed.
+type WordInfo struct {
+	Index      int    // the 1-based position of the word in the command
+	Value      string // the word, with its quotes and escapes processed
+	ByteOffset int    // byte offset of the word's source text within the input
+	RuneLen    int    // length of the word's source text in runes
+}
+
+// SplitDetailed splits input like Split, and returns each word together with
+// its position in input. ByteOffset and RuneLen describe the source text of
+// the word, quotes included, which is what a completion or help UI needs to
+// find the word under the cursor.
+func SplitDetailed(input string) ([]WordInfo, error) {
+	var opts Options
+	infos := make([]WordInfo, 0)
+	err := opts.scan(input, func(tok Token) error {
+		infos = append(infos, WordInfo{
+			Index:      len(infos) + 1,
+			Value:      tok.Value,
+			ByteOffset: tok.Offset,
+			RuneLen:    utf8.RuneCountInString(tok.Raw),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
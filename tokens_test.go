@@ -0,0 +1,155 @@
+package shellquote
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	var opts Options
+	tokens, err := opts.Tokens("  foo 'bar baz'\t")
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []Token{
+		{Word, "foo", "foo", 2, Unquoted},
+		{Word, "bar baz", "'bar baz'", 6, SingleQuoted},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("got %#v, expected %#v", tokens, expected)
+	}
+}
+
+func TestTokensPreserveWhitespace(t *testing.T) {
+	opts := Options{PreserveWhitespace: true}
+	for _, input := range preserveWhitespaceTest {
+		tokens, err := opts.Tokens(input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", input, err)
+			continue
+		}
+		var raw strings.Builder
+		for _, tok := range tokens {
+			if tok.Offset != raw.Len() {
+				t.Errorf("Input %q, token %q has offset %d, expected %d", input, tok.Raw, tok.Offset, raw.Len())
+			}
+			raw.WriteString(tok.Raw)
+		}
+		if raw.String() != input {
+			t.Errorf("Input %q, reconstructed %q", input, raw.String())
+		}
+	}
+
+	tokens, _ := opts.Tokens(" \t a  b")
+	expected := []Token{
+		{Whitespace, " \t ", " \t ", 0, Unquoted},
+		{Word, "a", "a", 3, Unquoted},
+		{Whitespace, "  ", "  ", 4, Unquoted},
+		{Word, "b", "b", 6, Unquoted},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("got %#v, expected %#v", tokens, expected)
+	}
+}
+
+func TestTokensQuote(t *testing.T) {
+	var opts Options
+	tokens, err := opts.Tokens(`plain 'single' "double" a\?b a'b'"c" ''`)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []QuoteKind{Unquoted, SingleQuoted, DoubleQuoted, Escaped, SingleQuoted | DoubleQuoted, SingleQuoted}
+	if len(tokens) != len(expected) {
+		t.Fatalf("got %d tokens, expected %d", len(tokens), len(expected))
+	}
+	for i, tok := range tokens {
+		if tok.Quote != expected[i] {
+			t.Errorf("token %q has quote kind %v, expected %v", tok.Raw, tok.Quote, expected[i])
+		}
+	}
+}
+
+func TestRawWords(t *testing.T) {
+	input := "echo  'a b'\t\"c \\\"d\\\" $x\" e\\ f \\\nnext ''"
+	output, err := RawWords(input)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []string{"echo", "'a b'", `"c \"d\" $x"`, `e\ f`, "next", "''"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	words, _ := Split(input)
+	resplit, err := Split(strings.Join(output, " "))
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if !reflect.DeepEqual(resplit, words) {
+		t.Errorf("got %q after re-splitting, expected %q", resplit, words)
+	}
+
+	if _, err := RawWords(`a "b`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+}
+
+func TestSplitKinds(t *testing.T) {
+	input := `grep -e 'a b' "$x" a\ b "it"'s' plain`
+	words, kinds, err := SplitKinds(input)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expectedWords, _ := Split(input)
+	expectedKinds := []QuoteKind{Unquoted, Unquoted, SingleQuoted, DoubleQuoted, Escaped, SingleQuoted | DoubleQuoted, Unquoted}
+	if !reflect.DeepEqual(words, expectedWords) {
+		t.Errorf("got words %q, expected %q", words, expectedWords)
+	}
+	if !reflect.DeepEqual(kinds, expectedKinds) {
+		t.Errorf("got kinds %v, expected %v", kinds, expectedKinds)
+	}
+}
+
+func TestSplitStats(t *testing.T) {
+	input := `git commit -m 'fix it' --author "A U Thor" a\ b 'x'"y" \$z`
+	words, stats, err := SplitStats(input)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	if expected, _ := Split(input); !reflect.DeepEqual(words, expected) {
+		t.Errorf("got words %q, expected %q", words, expected)
+	}
+	expected := Stats{Unquoted: 4, SingleQuoted: 2, DoubleQuoted: 2, Escaped: 2}
+	if stats != expected {
+		t.Errorf("got %+v, expected %+v", stats, expected)
+	}
+}
+
+func TestSplitDetailed(t *testing.T) {
+	output, err := SplitDetailed(`  grep 'héllo wörld' naïve\ ✓ x`)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []WordInfo{
+		{1, "grep", 2, 4},
+		{2, "héllo wörld", 7, 13},
+		{3, "naïve ✓", 23, 8},
+		{4, "x", 35, 1},
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %#v, expected %#v", output, expected)
+	}
+	if _, err := SplitDetailed(`a 'b`); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedSingleQuoteError", err)
+	}
+}
+
+var preserveWhitespaceTest = []string{
+	"",
+	"hello",
+	"   leading",
+	"trailing \t\n",
+	"  irregular \t  spacing\n\n 'with  quotes'   here  ",
+	"escaped \\\n   newline",
+}
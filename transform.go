@@ -0,0 +1,15 @@
+package shellquote
+
+// SplitTransform splits input like Split, then replaces each word with the
+// result of calling tr with the word and the QuoteKind describing how it was
+// quoted. This allows, for example, normalizing unquoted flags while leaving
+// quoted literals alone.
+func SplitTransform(input string, tr func(word string, kind QuoteKind) string) ([]string, error) {
+	var opts Options
+	words := make([]string, 0)
+	err := opts.scan(input, func(tok Token) error {
+		words = append(words, tr(tok.Value, tok.Quote))
+		return nil
+	})
+	return words, err
+}
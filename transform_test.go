@@ -0,0 +1,28 @@
+package shellquote
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitTransform(t *testing.T) {
+	upperUnquoted := func(word string, kind QuoteKind) string {
+		if kind == Unquoted {
+			return strings.ToUpper(word)
+		}
+		return word
+	}
+	output, err := SplitTransform(`cmd -flag 'single quoted' "double quoted" mixed'Case'`, upperUnquoted)
+	if err != nil {
+		t.Fatalf("got error %#v", err)
+	}
+	expected := []string{"CMD", "-FLAG", "single quoted", "double quoted", "mixedCase"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+
+	if _, err := SplitTransform("don't", upperUnquoted); err != UnterminatedSingleQuoteError {
+		t.Errorf("got error %#v, expected %#v", err, UnterminatedSingleQuoteError)
+	}
+}
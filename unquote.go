@@ -3,6 +3,7 @@ package shellquote
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"unicode/utf8"
@@ -12,14 +13,32 @@ var (
 	UnterminatedSingleQuoteError = errors.New("Unterminated single-quoted string")
 	UnterminatedDoubleQuoteError = errors.New("Unterminated double-quoted string")
 	UnterminatedEscapeError      = errors.New("Unterminated backslash-escape")
+	ErrEmptyInput                = errors.New("Input contains no words")
+	ErrOutputTooLong             = errors.New("Output too long")
+	ErrAutoClosed                = errors.New("Unterminated quote closed at end of input")
 )
 
+// SyntaxError records the location of an error in the input.
+type SyntaxError struct {
+	Offset int   // byte offset of the error within the input
+	Err    error // the underlying error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	splitChars        = " \n\t"
 	singleChar        = '\''
 	doubleChar        = '"'
 	escapeChar        = '\\'
 	doubleEscapeChars = "$`\"\n\\"
+	byteOrderMark     = "\uFEFF"
 )
 
 // Split splits a string according to /bin/sh's word-splitting rules. It
@@ -28,45 +47,262 @@ var (
 // other sort of expansion, including brace expansion, shell expansion, or
 // pathname expansion.
 //
+// Outside of quotes, a backslash makes the character after it a literal part
+// of the word it appears in, so `\ foo` splits into the single word " foo"
+// and `\;` into ";". A backslash-escaped newline is removed entirely, and
+// outside of a word it acts as a separator.
+//
 // If the given input has an unterminated quoted string or ends in a
 // backslash-escape, one of UnterminatedSingleQuoteError,
 // UnterminatedDoubleQuoteError, or UnterminatedEscapeError is returned.
 func Split(input string) (words []string, err error) {
-	var buf bytes.Buffer
+	var opts Options
+	return opts.Split(input)
+}
+
+// SplitLenient is like Split, except that an unterminated single- or
+// double-quoted string is treated as if it were closed at the end of input.
+// The words are returned together with ErrAutoClosed, so that callers can
+// tell the input wasn't actually complete. This is meant for best-effort
+// parsing of truncated or partially typed commands.
+func SplitLenient(input string) (words []string, err error) {
+	var opts Options
+	return opts.SplitLenient(input)
+}
+
+// SplitLenient splits input like the package-level SplitLenient, honoring
+// the settings in o. With o.ANSIC set, an unterminated $'...' string is
+// closed as well.
+func (o *Options) SplitLenient(input string) (words []string, err error) {
+	lenient := *o
+	lenient.autoClose = true
+	return lenient.Split(input)
+}
+
+// Split splits input like the package-level Split, honoring the settings in
+// o.
+func (o *Options) Split(input string) (words []string, err error) {
 	words = make([]string, 0)
+	err = o.scan(input, func(tok Token) error {
+		if tok.Kind != Whitespace {
+			words = append(words, tok.Value)
+		}
+		return nil
+	})
+	if err == nil && o.ErrorOnEmpty && len(words) == 0 {
+		err = ErrEmptyInput
+	}
+	return
+}
+
+// errStopScan can be returned by a scan callback to stop scanning before the
+// end of the input. It is never returned to the caller of scan.
+var errStopScan = errors.New("stop scan")
+
+// scan walks input, calling fn for each token found. Whitespace tokens are
+// only reported if o.PreserveWhitespace is set.
+func (o *Options) scan(input string, fn func(tok Token) error) error {
+	err := o.scanTokens(input, fn)
+	if err == errStopScan {
+		err = nil
+	} else if serr, ok := err.(*SyntaxError); ok {
+		// syntax errors have always been returned without their location
+		err = serr.Err
+	}
+	return err
+}
+
+// scanTokens is like scan, but returns syntax errors in the input as a
+// *SyntaxError.
+func (o *Options) scanTokens(input string, fn func(tok Token) error) error {
+	var buf bytes.Buffer
+	orig := input
+	// total is the number of bytes in all the words found so far
+	total := 0
+	// cmdPos is set when the next word would be the start of a command,
+	// where "{" and "}" are reserved words
+	cmdPos := true
+	// afterDelim is set while the current field has been ended by a
+	// delimiter other than whitespace, for PreserveEmptyFields
+	afterDelim := true
+	var empty []int
+	// closeErr is the ErrAutoClosed error to return once the words of the
+	// last, auto-closed word have been reported
+	var closeErr error
+	if o.StripBOM {
+		input = strings.TrimPrefix(input, byteOrderMark)
+	}
 
 	for len(input) > 0 {
 		// skip any splitChars at the start
-		c, l := utf8.DecodeRuneInString(input)
-		if strings.ContainsRune(splitChars, c) {
-			input = input[l:]
+		start := input
+		for len(input) > 0 {
+			c, l := utf8.DecodeRuneInString(input)
+			if o.isDelim(c, false) {
+				if o.PreserveEmptyFields && !strings.ContainsRune(splitChars, c) {
+					if afterDelim {
+						empty = append(empty, len(orig)-len(input))
+					}
+					afterDelim = true
+				}
+				input = input[l:]
+				continue
+			} else if c == escapeChar {
+				// Look ahead for escaped newline so we can skip over it
+				next := input[l:]
+				if len(next) == 0 {
+					return &SyntaxError{len(orig) - len(input), UnterminatedEscapeError}
+				}
+				c2, l2 := utf8.DecodeRuneInString(next)
+				if c2 == '\n' {
+					input = next[l2:]
+					continue
+				}
+			}
+			break
+		}
+		skipped := start[:len(start)-len(input)]
+		if strings.Count(skipped, "\n") > strings.Count(skipped, "\\\n") {
+			cmdPos = true
+		}
+		if o.PreserveWhitespace && len(skipped) > 0 {
+			raw := skipped
+			tok := Token{Kind: Whitespace, Value: raw, Raw: raw, Offset: len(orig) - len(start)}
+			if err := fn(tok); err != nil {
+				return err
+			}
+		}
+		for _, offset := range empty {
+			if err := fn(Token{Kind: Word, Offset: offset}); err != nil {
+				return err
+			}
+		}
+		empty = empty[:0]
+		if len(input) == 0 {
+			break
+		}
+		afterDelim = false
+
+		if o.Comments && input[0] == '#' {
+			// skip to the end of the line, leaving the newline as a separator
+			if i := strings.IndexByte(input, '\n'); i != -1 {
+				input = input[i:]
+			} else {
+				input = ""
+			}
 			continue
-		} else if c == escapeChar {
-			// Look ahead for escaped newline so we can skip over it
-			next := input[l:]
-			if len(next) == 0 {
-				err = UnterminatedEscapeError
-				return
-			}
-			c2, l2 := utf8.DecodeRuneInString(next)
-			if c2 == '\n' {
-				input = next[l2:]
+		}
+
+		if o.Operators {
+			if op := matchOperator(input); op != "" {
+				tok := Token{Kind: Operator, Value: op, Raw: op, Offset: len(orig) - len(input)}
+				if err := fn(tok); err != nil {
+					return err
+				}
+				input = input[len(op):]
+				cmdPos = startsCommand(op)
 				continue
 			}
 		}
 
-		var word string
-		word, input, err = splitWord(input, &buf)
+		limit := -1
+		if o.MaxOutputLen > 0 {
+			limit = o.MaxOutputLen - total
+		}
+		fields, kind, rest, err := o.splitWord(input, &buf, limit)
 		if err != nil {
-			return
+			serr, ok := err.(*SyntaxError)
+			if ok {
+				serr.Offset += len(orig) - len(input)
+			}
+			if !ok || serr.Err != ErrAutoClosed {
+				return err
+			}
+			closeErr = err
+		}
+		for _, field := range fields {
+			total += len(field)
+		}
+		raw := input[:len(input)-len(rest)]
+		tokKind := Word
+		if o.Operators && cmdPos && (raw == "{" || raw == "}") {
+			tokKind = Keyword
 		}
-		words = append(words, word)
+		cmdPos = tokKind == Keyword && raw == "{"
+		for _, field := range fields {
+			// a word that expanded to several fields has its source text
+			// attached to the first one only
+			tok := Token{Kind: tokKind, Value: field, Raw: raw, Offset: len(orig) - len(input), Quote: kind}
+			if err := fn(tok); err != nil {
+				return err
+			}
+			raw = ""
+		}
+		input = rest
 	}
-	return
+	return closeErr
+}
+
+// isDelim reports whether c separates words. Characters inside quotes are
+// only delimiters if o.IsDelim says so.
+func (o *Options) isDelim(c rune, inQuote bool) bool {
+	if o.IsDelim != nil {
+		return o.IsDelim(c, inQuote)
+	}
+	return !inQuote && strings.ContainsRune(splitChars, c)
 }
 
-func splitWord(input string, buf *bytes.Buffer) (word string, remainder string, err error) {
+// splitWord splits off the word at the start of input. Usually this results
+// in a single field, but parameter expansion may produce any number of them.
+// Errors are returned as a *SyntaxError with an offset relative to the start
+// of the word. If limit isn't negative, the fields may be at most that many
+// bytes long in total, and splitting stops with ErrOutputTooLong as soon as
+// they grow past it.
+func (o *Options) splitWord(input string, buf *bytes.Buffer, limit int) (fields []string, kind QuoteKind, remainder string, err error) {
 	buf.Reset()
+	start := input
+	// open is the offset of the quote or escape currently being processed
+	open := 0
+	// keep is set once the current field is known to exist even if it ends
+	// up empty, which is the case for anything quoted.
+	keep := false
+	// parens is the number of unquoted "(" in the word still to be closed
+	parens := 0
+	// size is the total length of the fields split off so far
+	size := 0
+	tooLong := func() bool {
+		return limit >= 0 && size+buf.Len() > limit
+	}
+	addValues := func(values []string, quoted bool) bool {
+		for i, v := range values {
+			if i > 0 {
+				if keep || buf.Len() > 0 {
+					fields = append(fields, buf.String())
+					size += buf.Len()
+				}
+				buf.Reset()
+				keep = quoted
+			}
+			buf.WriteString(v)
+			if tooLong() {
+				return false
+			}
+		}
+		if quoted && len(values) > 0 {
+			keep = true
+		}
+		return true
+	}
+
+	if o.ExpandTilde != nil {
+		if home, n := o.expandTilde(input); n > 0 {
+			buf.WriteString(home)
+			if tooLong() {
+				return nil, 0, "", &SyntaxError{0, ErrOutputTooLong}
+			}
+			input = input[n:]
+		}
+	}
 
 raw:
 	{
@@ -76,19 +312,67 @@ raw:
 			cur = cur[l:]
 			if c == singleChar {
 				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				open = len(start) - len(cur) - l
 				input = cur
+				kind |= SingleQuoted
+				keep = true
 				goto single
 			} else if c == doubleChar {
 				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				open = len(start) - len(cur) - l
 				input = cur
+				kind |= DoubleQuoted
+				if o.Lookup == nil || !(strings.HasPrefix(cur, "$@\"") || strings.HasPrefix(cur, "${@}\"")) {
+					// "$@" (or "${@}") is the one quoted string that can
+					// expand to nothing
+					keep = true
+				}
 				goto double
 			} else if c == escapeChar {
 				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				open = len(start) - len(cur) - l
 				input = cur
+				kind |= Escaped
+				keep = true
 				goto escape // escape routine handle them all
-			} else if strings.ContainsRune(splitChars, c) {
+			} else if c == '$' && o.ANSIC && strings.HasPrefix(cur, "'") {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				open = len(start) - len(cur) - l
+				input = cur[1:]
+				kind |= ANSICQuoted
+				keep = true
+				goto ansi
+			} else if c == '$' && o.OpaqueSubstitution && strings.HasPrefix(cur, "(") {
+				// keep the whole substitution as part of the word
+				n, err := substitutionLen(cur[1:])
+				if err != nil {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, err}
+				}
+				cur = cur[1+n:]
+			} else if c == '$' && o.Lookup != nil {
+				values, n, err := o.expand(cur, false)
+				if err != nil {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, err}
+				}
+				if n > 0 {
+					buf.WriteString(input[0 : len(input)-len(cur)-l])
+					if !addValues(values, false) {
+						return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, ErrOutputTooLong}
+					}
+					cur = cur[n:]
+					input = cur
+				}
+			} else if o.isDelim(c, false) || (o.Operators && strings.ContainsRune(operatorChars, c)) {
 				buf.WriteString(input[0 : len(input)-len(cur)-l])
-				return buf.String(), cur, nil
+				input = input[len(input)-len(cur)-l:]
+				goto done
+			} else if c == '(' && o.OpaqueSubstitution {
+				parens++
+			} else if c == ')' && o.OpaqueSubstitution {
+				if parens == 0 {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, ErrUnexpectedClose}
+				}
+				parens--
 			}
 		}
 		if len(input) > 0 {
@@ -101,35 +385,27 @@ raw:
 escape:
 	{
 		if len(input) == 0 {
-			return "", "", UnterminatedEscapeError
+			return nil, 0, "", &SyntaxError{open, UnterminatedEscapeError}
 		}
 		c, l := utf8.DecodeRuneInString(input)
 		cur := input
 		cur = cur[l:]
-		if strings.ContainsRune(doubleEscapeChars, c) {
-			buf.WriteString(input[0 : len(input)-len(cur)-l])
+		if c == '\n' {
+			// a backslash-escaped newline is elided from the output entirely
+		} else {
 			// Windows accepts backslash in file path
-			if os.PathSeparator == escapeChar {
-				if len(cur) > 0 {
-					next := rune(cur[0])
-					switch next {
-					case singleChar, doubleChar, escapeChar, 'n':
-					default:
-						buf.WriteString(string(escapeChar))
-					}
-				} else {
-					buf.WriteString(input[:l])
+			if (c == singleChar || strings.ContainsRune(doubleEscapeChars, c)) && os.PathSeparator == escapeChar && len(cur) > 0 {
+				next := rune(cur[0])
+				switch next {
+				case singleChar, doubleChar, escapeChar, 'n':
+				default:
+					buf.WriteString(string(escapeChar))
 				}
 			}
-		} else {
-			buf.WriteString(string(escapeChar))
+			// any other escaped character, separators and operators
+			// included, is a literal part of the word
 			buf.WriteString(input[:l])
 		}
-		// if c == '\n' {
-		// 	// a backslash-escaped newline is elided from the output entirely
-		// } else {
-		// 	buf.WriteString(input[:l])
-		// }
 		input = input[l:]
 	}
 	goto raw
@@ -137,56 +413,178 @@ escape:
 single:
 	{
 		i := strings.IndexRune(input, singleChar)
-		if i == -1 {
-			return "", "", UnterminatedSingleQuoteError
+		closed := i != -1
+		if !closed {
+			if !o.autoClose {
+				return nil, 0, "", &SyntaxError{open, UnterminatedSingleQuoteError}
+			}
+			i = len(input)
+		}
+		if o.KeepSingleQuotes {
+			buf.WriteRune(singleChar)
+		}
+		quoted := input[0:i]
+		if o.StripCRInQuotes {
+			quoted = strings.ReplaceAll(quoted, "\r", "")
+		}
+		if o.IsDelim != nil {
+			for {
+				j := strings.IndexFunc(quoted, func(r rune) bool { return o.IsDelim(r, true) })
+				if j == -1 {
+					break
+				}
+				_, l := utf8.DecodeRuneInString(quoted[j:])
+				buf.WriteString(quoted[:j])
+				fields = append(fields, buf.String())
+				size += buf.Len()
+				buf.Reset()
+				quoted = quoted[j+l:]
+			}
+		}
+		buf.WriteString(quoted)
+		if o.KeepSingleQuotes {
+			buf.WriteRune(singleChar)
+		}
+		if !closed {
+			goto autoClosed
 		}
-		buf.WriteString(input[0:i])
 		input = input[i+1:]
 		goto raw
 	}
 
+ansi:
+	{
+		cur := input
+		for len(cur) > 0 {
+			c, l := utf8.DecodeRuneInString(cur)
+			cur = cur[l:]
+			if c == singleChar {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				input = cur
+				goto raw
+			} else if c == '\r' && o.StripCRInQuotes {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				input = cur
+			} else if c == escapeChar {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				n := ansiEscape(cur, buf)
+				if n == 0 {
+					// only the trailing backslash is left
+					input = input[len(input)-len(cur)-l:]
+					break
+				}
+				if tooLong() {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, ErrOutputTooLong}
+				}
+				cur = cur[n:]
+				input = cur
+			}
+		}
+		if o.autoClose {
+			buf.WriteString(input)
+			goto autoClosed
+		}
+		return nil, 0, "", &SyntaxError{open, UnterminatedSingleQuoteError}
+	}
+
 double:
+	if o.KeepDoubleQuotes {
+		// copy the string verbatim, only looking at escapes to find its end
+		cur := input
+		for len(cur) > 0 {
+			c, l := utf8.DecodeRuneInString(cur)
+			cur = cur[l:]
+			if c == doubleChar {
+				buf.WriteRune(doubleChar)
+				buf.WriteString(input[0 : len(input)-len(cur)])
+				input = cur
+				goto raw
+			} else if c == escapeChar && len(cur) > 0 {
+				_, l2 := utf8.DecodeRuneInString(cur)
+				cur = cur[l2:]
+			}
+		}
+		if o.autoClose {
+			buf.WriteRune(doubleChar)
+			buf.WriteString(input)
+			buf.WriteRune(doubleChar)
+			goto autoClosed
+		}
+		return nil, 0, "", &SyntaxError{open, UnterminatedDoubleQuoteError}
+	}
 	{
-		if len(input) == 0 {
-			cur := input
-			for len(cur) > 0 {
-				c, l := utf8.DecodeRuneInString(cur)
-				cur = cur[l:]
-				if c == doubleChar {
-					buf.WriteString(input[0 : len(input)-len(cur)-l])
+		cur := input
+		for len(cur) > 0 {
+			c, l := utf8.DecodeRuneInString(cur)
+			cur = cur[l:]
+			if c == doubleChar {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				input = cur
+				goto raw
+			} else if c == '\r' && o.StripCRInQuotes {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				input = cur
+			} else if o.IsDelim != nil && o.IsDelim(c, true) {
+				buf.WriteString(input[0 : len(input)-len(cur)-l])
+				fields = append(fields, buf.String())
+				size += buf.Len()
+				buf.Reset()
+				input = cur
+			} else if c == escapeChar {
+				// bash only supports certain escapes in double-quoted strings
+				c2, l2 := utf8.DecodeRuneInString(cur)
+				cur = cur[l2:]
+				if strings.ContainsRune(doubleEscapeChars, c2) {
+					buf.WriteString(input[0 : len(input)-len(cur)-l-l2])
+					if c2 == '\n' {
+						// newline is special, skip the backslash entirely
+					} else {
+						buf.WriteRune(c2)
+					}
 					input = cur
-					goto raw
-				} else if c == escapeChar {
+				}
+			} else if c == '$' && o.OpaqueSubstitution && strings.HasPrefix(cur, "(") {
+				n, err := substitutionLen(cur[1:])
+				if err != nil {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, err}
+				}
+				cur = cur[1+n:]
+			} else if c == '$' && o.Lookup != nil {
+				values, n, err := o.expand(cur, true)
+				if err != nil {
+					return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, err}
+				}
+				if n > 0 {
 					buf.WriteString(input[0 : len(input)-len(cur)-l])
+					if !addValues(values, true) {
+						return nil, 0, "", &SyntaxError{len(start) - len(cur) - l, ErrOutputTooLong}
+					}
+					cur = cur[n:]
 					input = cur
-					goto escape
-				}
-			}
-		}
-		return "", "", UnterminatedDoubleQuoteError
-		// 	// bash only supports certain escapes in double-quoted strings
-		// c2, l2 := utf8.DecodeRuneInString(cur)
-		// cur = cur[l2:]
-		// if strings.ContainsRune(doubleEscapeChars, c2) {
-		// 	buf.WriteString(input[0 : len(input)-len(cur)-l-l2])
-		// 	if os.PathSeparator == escapeChar {
-		// 		if len(cur) > 0 {
-		// 			next := rune(cur[0])
-		// 			switch next {
-		// 			case singleChar, doubleChar, escapeChar, 'n':
-		// 			default:
-		// 				buf.WriteString(string(escapeChar))
-		// 			}
-		// 		} else {
-		// 			buf.WriteString(string(escapeChar))
-		// 		}
-		// 	}
-		// 	input = cur
-		// 	goto raw
-		// }
-		// }
+				}
+			}
+		}
+		if o.autoClose {
+			buf.WriteString(input)
+			goto autoClosed
+		}
+		return nil, 0, "", &SyntaxError{open, UnterminatedDoubleQuoteError}
 	}
 
 done:
-	return buf.String(), input, nil
+	if tooLong() {
+		return nil, 0, "", &SyntaxError{0, ErrOutputTooLong}
+	}
+	if keep || buf.Len() > 0 {
+		fields = append(fields, buf.String())
+	}
+	return fields, kind, input, nil
+
+autoClosed:
+	// the end of input closed the quoted string, so the word ends there
+	if tooLong() {
+		return nil, 0, "", &SyntaxError{0, ErrOutputTooLong}
+	}
+	fields = append(fields, buf.String())
+	return fields, kind, "", &SyntaxError{open, ErrAutoClosed}
 }
@@ -2,6 +2,7 @@ package shellquote
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -25,6 +26,168 @@ func TestErrorSplit(t *testing.T) {
 	}
 }
 
+func TestErrorOnEmpty(t *testing.T) {
+	for _, elem := range errorOnEmptyTest {
+		for _, errorOnEmpty := range []bool{false, true} {
+			opts := Options{ErrorOnEmpty: errorOnEmpty}
+			output, err := opts.Split(elem.input)
+			if errorOnEmpty && len(elem.output) == 0 {
+				if err != ErrEmptyInput {
+					t.Errorf("Input %q, got error %#v, expected %#v", elem.input, err, ErrEmptyInput)
+				}
+			} else if err != nil {
+				t.Errorf("Input %q, got error %#v", elem.input, err)
+			} else if !reflect.DeepEqual(output, elem.output) {
+				t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+			}
+		}
+	}
+}
+
+func TestStripBOM(t *testing.T) {
+	for _, elem := range stripBOMTest {
+		opts := Options{StripBOM: elem.strip}
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestStripCRInQuotes(t *testing.T) {
+	for _, elem := range stripCRInQuotesTest {
+		opts := Options{StripCRInQuotes: elem.strip, ANSIC: true}
+		output, err := opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestKeepQuotes(t *testing.T) {
+	for _, elem := range keepQuotesTest {
+		output, err := elem.opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+	opts := Options{KeepDoubleQuotes: true}
+	if _, err := opts.Split(`a "b\"`); err != UnterminatedDoubleQuoteError {
+		t.Errorf("got error %#v, expected UnterminatedDoubleQuoteError", err)
+	}
+}
+
+func TestIsDelim(t *testing.T) {
+	for _, elem := range isDelimTest {
+		output, err := elem.opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	for _, elem := range commentsTest {
+		output, err := elem.opts.Split(elem.input)
+		if err != nil {
+			t.Errorf("Input %q, got error %#v", elem.input, err)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+func TestMaxOutputLen(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+	opts := Options{MaxOutputLen: 100, Lookup: lookupFrom(map[string][]string{"x": {long}})}
+	if _, err := opts.Split("echo $x"); err != ErrOutputTooLong {
+		t.Errorf("got error %#v, expected ErrOutputTooLong", err)
+	}
+	if _, err := opts.Split(strings.Repeat("abcd ", 26)); err != ErrOutputTooLong {
+		t.Errorf("got error %#v, expected ErrOutputTooLong", err)
+	}
+	output, err := opts.Split(strings.Repeat("abcd ", 25))
+	if err != nil {
+		t.Errorf("got error %#v", err)
+	} else if len(output) != 25 {
+		t.Errorf("got %d words, expected 25", len(output))
+	}
+
+	opts = Options{MaxOutputLen: 10, ANSIC: true}
+	if _, err := opts.Split(`a $'\u00e9\u00e9\u00e9\u00e9\u00e9'`); err != ErrOutputTooLong {
+		t.Errorf("got error %#v, expected ErrOutputTooLong", err)
+	}
+
+	// a single word made of many expansions stops at the one that crosses
+	// the limit, rather than being built in full first
+	opts = Options{MaxOutputLen: 100, Lookup: lookupFrom(map[string][]string{"x": {"abcdefghij"}})}
+	err = opts.scanTokens("echo "+strings.Repeat("$x", 1000), func(Token) error { return nil })
+	if serr, ok := err.(*SyntaxError); !ok || serr.Err != ErrOutputTooLong || serr.Offset != 23 {
+		t.Errorf("got error %#v, expected ErrOutputTooLong at offset 23", err)
+	}
+}
+
+func TestSplitLenient(t *testing.T) {
+	for _, elem := range splitLenientTest {
+		output, err := SplitLenient(elem.input)
+		if err != elem.error {
+			t.Errorf("Input %q, got error %#v, expected %#v", elem.input, err, elem.error)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+
+	opts := Options{ANSIC: true}
+	for _, elem := range splitLenientANSICTest {
+		output, err := opts.SplitLenient(elem.input)
+		if err != elem.error {
+			t.Errorf("Input %q, got error %#v, expected %#v", elem.input, err, elem.error)
+		} else if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+const (
+	benchSimpleInput  = "git log --oneline --graph --decorate --all -n 100 -- path/to/some/file.go"
+	benchQuotedInput  = `git commit -m "fix the thing" --author='A. U. Thor <a@example.com>' -- "file one.go" 'file two.go'`
+	benchEscapedInput = `cp some\ file\ with\ spaces.txt another\ dir/with\ more\ spaces/ \"quoted\" \$HOME`
+)
+
+func BenchmarkSplitSimple(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Split(benchSimpleInput)
+	}
+}
+
+// BenchmarkStringsFields is a baseline for BenchmarkSplitSimple, since
+// strings.Fields gives the same result for input without quotes or escapes.
+func BenchmarkStringsFields(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		strings.Fields(benchSimpleInput)
+	}
+}
+
+func BenchmarkSplitQuoted(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Split(benchQuotedInput)
+	}
+}
+
+func BenchmarkSplitEscaped(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Split(benchEscapedInput)
+	}
+}
+
 var simpleSplitTest = []struct {
 	input  string
 	output []string
@@ -33,14 +196,24 @@ var simpleSplitTest = []struct {
 	{"hello goodbye", []string{"hello", "goodbye"}},
 	{"hello   goodbye", []string{"hello", "goodbye"}},
 	{"glob* test?", []string{"glob*", "test?"}},
-	{"don\\'t you know the dewey decimal system\\?", []string{"don't", "you", "know", "the", "dewey", "decimal", "system\\?"}},
+	{"don\\'t you know the dewey decimal system\\?", []string{"don't", "you", "know", "the", "dewey", "decimal", "system?"}},
 	{"'don'\\''t you know the dewey decimal system?'", []string{"don't you know the dewey decimal system?"}},
 	{"one '' two", []string{"one", "", "two"}},
-	{"text with\\\na backslash-escaped newline", []string{"text", "with\\a", "backslash-escaped", "newline"}},
+	{"text with\\\na backslash-escaped newline", []string{"text", "witha", "backslash-escaped", "newline"}},
 	{"text \"with\na\" quoted newline", []string{"text", "with\na", "quoted", "newline"}},
 	{"\"quoted\\d\\\\\\\" text with\\\na backslash-escaped newline\"", []string{"quoted\\d\\\" text witha backslash-escaped newline"}},
+	{`find . -exec rm {} \; a\|b \*.go \{x,y} \& \<\> \(\) \# \~ \!`, []string{"find", ".", "-exec", "rm", "{}", ";", "a|b", "*.go", "{x,y}", "&", "<>", "()", "#", "~", "!"}},
+	{`\a\b\c \é \\\\`, []string{"abc", "é", `\\`}},
 	{"text with an escaped \\\n newline in the middle", []string{"text", "with", "an", "escaped", "newline", "in", "the", "middle"}},
 	{"foo\"bar\"baz", []string{"foobarbaz"}},
+	{"\\ foo", []string{" foo"}},
+	{"\\ \\ foo", []string{"  foo"}},
+	{"\\  foo", []string{" ", "foo"}},
+	{"\\\tfoo", []string{"\tfoo"}},
+	{"a\\ b c", []string{"a b", "c"}},
+	{"foo\\ ", []string{"foo "}},
+	{"foo \\ ", []string{"foo", " "}},
+	{"\\\n\\ foo", []string{" foo"}},
 }
 
 var errorSplitTest = []struct {
@@ -53,3 +226,128 @@ var errorSplitTest = []struct {
 	{"foo\\", UnterminatedEscapeError},
 	{"   \\", UnterminatedEscapeError},
 }
+
+var errorOnEmptyTest = []struct {
+	input  string
+	output []string
+}{
+	{"", []string{}},
+	{" \t\n ", []string{}},
+	{"  \\\n  ", []string{}},
+	{"ls -l", []string{"ls", "-l"}},
+	{"''", []string{""}},
+}
+
+var stripBOMTest = []struct {
+	strip  bool
+	input  string
+	output []string
+}{
+	{true, "\uFEFFfoo bar", []string{"foo", "bar"}},
+	{true, "foo bar", []string{"foo", "bar"}},
+	{true, "\uFEFF  'foo' bar", []string{"foo", "bar"}},
+	{true, "\uFEFF", []string{}},
+	{true, "foo \uFEFFbar", []string{"foo", "\uFEFFbar"}},
+	{false, "\uFEFFfoo bar", []string{"\uFEFFfoo", "bar"}},
+}
+
+var stripCRInQuotesTest = []struct {
+	strip  bool
+	input  string
+	output []string
+}{
+	{true, "echo \"line one\r\nline two\r\n\"", []string{"echo", "line one\nline two\n"}},
+	{false, "echo \"line one\r\nline two\r\n\"", []string{"echo", "line one\r\nline two\r\n"}},
+	{true, "'a\r\nb' \"\\$x\r\\\"\"", []string{"a\nb", "$x\""}},
+	{false, "'a\r\nb'", []string{"a\r\nb"}},
+	{true, "$'a\r\\r'", []string{"a\r"}},
+}
+
+var keepQuotesTest = []struct {
+	opts   Options
+	input  string
+	output []string
+}{
+	{Options{KeepDoubleQuotes: true}, `'a' "b"`, []string{"a", `"b"`}},
+	{Options{KeepDoubleQuotes: true}, `x"a \" $y"z ''`, []string{`x"a \" $y"z`, ""}},
+	{Options{KeepDoubleQuotes: true, Lookup: lookupFrom(map[string][]string{"y": {"1"}})}, `"$y" $y`, []string{`"$y"`, "1"}},
+	{Options{KeepSingleQuotes: true}, `'a' "b" 'c d'e`, []string{"'a'", "b", "'c d'e"}},
+	{Options{KeepSingleQuotes: true, KeepDoubleQuotes: true}, `'a'"b" ''`, []string{`'a'"b"`, "''"}},
+}
+
+func commaDelim(r rune, inQuote bool) bool {
+	return r == ',' && !inQuote
+}
+
+func commaDelimAlways(r rune, inQuote bool) bool {
+	return r == ','
+}
+
+func colonDelim(r rune, inQuote bool) bool {
+	return !inQuote && (r == ':' || r == ' ')
+}
+
+var isDelimTest = []struct {
+	opts   Options
+	input  string
+	output []string
+}{
+	{Options{IsDelim: commaDelim}, `a,b c,"d,e",'f,g'`, []string{"a", "b c", "d,e", "f,g"}},
+	{Options{IsDelim: commaDelim}, `,,a,,b,`, []string{"a", "b"}},
+	{Options{IsDelim: commaDelim}, `a\,b,c`, []string{"a,b", "c"}},
+	{Options{IsDelim: commaDelim}, `a\ b`, []string{"a b"}},
+	{Options{IsDelim: commaDelimAlways}, `x"a,b"y,'c,d'`, []string{"xa", "by", "c", "d"}},
+	{Options{}, "a\tb\t c", []string{"a", "b", "c"}},
+	{Options{IsDelim: colonDelim, PreserveEmptyFields: true}, `a\:b:c`, []string{"a:b", "c"}},
+	{Options{IsDelim: colonDelim, PreserveEmptyFields: true}, `a\::b::\:c:`, []string{"a:", "b", "", ":c"}},
+	{Options{IsDelim: colonDelim, PreserveEmptyFields: true}, `:a : : b:`, []string{"", "a", "", "b"}},
+	{Options{IsDelim: colonDelim, PreserveEmptyFields: true}, `a:"":'b:c'`, []string{"a", "", "b:c"}},
+	{Options{IsDelim: colonDelim, PreserveEmptyFields: true}, `::`, []string{"", ""}},
+	{Options{IsDelim: colonDelim}, `a::b`, []string{"a", "b"}},
+	{Options{PreserveEmptyFields: true}, "a  \t b", []string{"a", "b"}},
+}
+
+var commentsTest = []struct {
+	opts   Options
+	input  string
+	output []string
+}{
+	{Options{Comments: true}, "# just a comment", []string{}},
+	{Options{Comments: true}, "  \t# a comment after whitespace", []string{}},
+	{Options{Comments: true}, "", []string{}},
+	{Options{Comments: true}, "#", []string{}},
+	{Options{Comments: true}, "a b # c 'd", []string{"a", "b"}},
+	{Options{Comments: true}, "a # c\nb #d\n#e", []string{"a", "b"}},
+	{Options{Comments: true}, `a#b "#c" '#d' \#e`, []string{"a#b", "#c", "#d", "#e"}},
+	{Options{Comments: true, Operators: true}, "a;#b\nc", []string{"a", ";", "c"}},
+	{Options{}, "a # b", []string{"a", "#", "b"}},
+}
+
+var splitLenientTest = []struct {
+	input  string
+	output []string
+	error  error
+}{
+	{"a 'b c", []string{"a", "b c"}, ErrAutoClosed},
+	{`a "b 'c`, []string{"a", "b 'c"}, ErrAutoClosed},
+	{`x 'it'"s`, []string{"x", "its"}, ErrAutoClosed},
+	{`a '`, []string{"a", ""}, ErrAutoClosed},
+	{`a "b \`, []string{"a", `b \`}, ErrAutoClosed},
+	{`a $'b\tc`, []string{"a", "$b\\tc"}, ErrAutoClosed},
+	{`a "$'b`, []string{"a", "$'b"}, ErrAutoClosed},
+	{"a 'b' c", []string{"a", "b", "c"}, nil},
+	{"", []string{}, nil},
+	{`a \`, []string{"a"}, UnterminatedEscapeError},
+}
+
+var splitLenientANSICTest = []struct {
+	input  string
+	output []string
+	error  error
+}{
+	{`a $'b\tc`, []string{"a", "b\tc"}, ErrAutoClosed},
+	{`a $'b\`, []string{"a", `b\`}, ErrAutoClosed},
+	{`a $'b\'`, []string{"a", "b'"}, ErrAutoClosed},
+	{`a $'b' "c`, []string{"a", "b", "c"}, ErrAutoClosed},
+	{`a $'b'`, []string{"a", "b"}, nil},
+}
@@ -0,0 +1,55 @@
+package shellquote
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FindWinExpansions returns the byte offsets of every %VAR% style expansion
+// in input, as cmd.exe would expand them. It only detects expansions, it
+// doesn't perform them. A variable name must start with a letter or an
+// underscore and can't contain whitespace, so "100%done" and "%%" aren't
+// reported.
+//
+// Quoting is ignored entirely: cmd.exe expands variables inside double
+// quotes, so an argument that is safely quoted for a POSIX shell may still
+// be expanded on Windows.
+func FindWinExpansions(input string) []int {
+	var offsets []int
+	for i := 0; i < len(input); {
+		j := strings.IndexByte(input[i:], '%')
+		if j == -1 {
+			break
+		}
+		start := i + j
+		if strings.HasPrefix(input[start:], "%%") {
+			// an escaped percent sign
+			i = start + 2
+			continue
+		}
+		if n := winVarLen(input[start+1:]); n > 0 {
+			offsets = append(offsets, start)
+			i = start + 1 + n + 1
+		} else {
+			i = start + 1
+		}
+	}
+	return offsets
+}
+
+// winVarLen returns the length of the variable name at the start of input if
+// it is followed by a closing %, or 0 otherwise.
+func winVarLen(input string) int {
+	for i, c := range input {
+		if c == '%' {
+			return i
+		}
+		if unicode.IsSpace(c) {
+			return 0
+		}
+		if i == 0 && c != '_' && !unicode.IsLetter(c) {
+			return 0
+		}
+	}
+	return 0
+}
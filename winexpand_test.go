@@ -0,0 +1,32 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindWinExpansions(t *testing.T) {
+	for _, elem := range findWinExpansionsTest {
+		output := FindWinExpansions(elem.input)
+		if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %v, expected %v", elem.input, output, elem.output)
+		}
+	}
+}
+
+var findWinExpansionsTest = []struct {
+	input  string
+	output []int
+}{
+	{"echo %PATH%", []int{5}},
+	{"100%done", nil},
+	{"copy %SRC% %DEST%", []int{5, 11}},
+	{`echo "%USERPROFILE%\docs"`, []int{6}},
+	{"echo '%HOME%'", []int{6}},
+	{"%ProgramFiles(x86)%", []int{0}},
+	{"50% off 20%", nil},
+	{"%1 %2", nil},
+	{"100%% %_var%", []int{6}},
+	{"%a b% %c%", []int{6}},
+	{"%unclosed", nil},
+}
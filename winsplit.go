@@ -0,0 +1,60 @@
+package shellquote
+
+import (
+	"bytes"
+	"strings"
+)
+
+// SplitWindows splits input into arguments the way the Microsoft C runtime
+// (and CommandLineToArgvW) splits a command line. Arguments are separated by
+// spaces and tabs, and double quotes group them. Backslashes are literal
+// unless they come before a double quote, in which case each pair of
+// backslashes becomes one backslash, and an odd backslash escapes the quote.
+// Inside double quotes, "" is a literal double quote.
+//
+// Unlike a POSIX shell, Windows doesn't consider an unterminated quote to be
+// an error, so the quoted argument simply runs until the end of input.
+// Single quotes have no special meaning.
+func SplitWindows(input string) []string {
+	args := make([]string, 0)
+	var buf bytes.Buffer
+	inArg, inQuote := false, false
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case (c == ' ' || c == '\t') && !inQuote:
+			if inArg {
+				args = append(args, buf.String())
+				buf.Reset()
+				inArg = false
+			}
+			continue
+		case c == '\\':
+			n := len(input[i:]) - len(strings.TrimLeft(input[i:], "\\"))
+			i += n - 1
+			if i+1 < len(input) && input[i+1] == '"' {
+				buf.WriteString(strings.Repeat("\\", n/2))
+				if n%2 == 1 {
+					buf.WriteByte('"')
+					i++
+				}
+			} else {
+				buf.WriteString(strings.Repeat("\\", n))
+			}
+		case c == '"':
+			if inQuote && i+1 < len(input) && input[i+1] == '"' {
+				buf.WriteByte('"')
+				i++
+			} else {
+				inQuote = !inQuote
+			}
+		default:
+			buf.WriteByte(c)
+		}
+		inArg = true
+	}
+	if inArg {
+		args = append(args, buf.String())
+	}
+	return args
+}
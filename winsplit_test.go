@@ -0,0 +1,32 @@
+package shellquote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWindows(t *testing.T) {
+	for _, elem := range splitWindowsTest {
+		output := SplitWindows(elem.input)
+		if !reflect.DeepEqual(output, elem.output) {
+			t.Errorf("Input %q, got %q, expected %q", elem.input, output, elem.output)
+		}
+	}
+}
+
+var splitWindowsTest = []struct {
+	input  string
+	output []string
+}{
+	{``, []string{}},
+	{"  a\tb  c ", []string{"a", "b", "c"}},
+	{`"a b c" d e`, []string{"a b c", "d", "e"}},
+	{`"ab\"c" "\\" d`, []string{`ab"c`, `\`, "d"}},
+	{`a\\\b d"e f"g h`, []string{`a\\\b`, "de fg", "h"}},
+	{`a\\\"b c d`, []string{`a\"b`, "c", "d"}},
+	{`a\\\\"b c" d e`, []string{`a\\b c`, "d", "e"}},
+	{`C:\Program Files\x "C:\Program Files\x"`, []string{`C:\Program`, `Files\x`, `C:\Program Files\x`}},
+	{`"a""b" 'c d' ""`, []string{`a"b`, "'c", "d'", ""}},
+	{`"unterminated arg`, []string{"unterminated arg"}},
+	{`trailing\`, []string{`trailing\`}},
+}